@@ -1,51 +1,121 @@
 package client
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"mafia-core/proto"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type client struct {
-	dialer      proto.MafiaClient
-	id          uint64
-	conn        *grpc.ClientConn
-	isConnected bool
+	dialer       proto.MafiaClient
+	id           uint64
+	conn         *grpc.ClientConn
+	isConnected  bool
+	lastSequence uint64
+	// moderatorToken is sent on every join to get the moderator rights
+	moderatorToken string
 }
 
 var cl = client{isConnected: false}
 
+// SetModeratorToken makes the client join the rooms with the moderator token of the server
+func SetModeratorToken(token string) {
+	cl.moderatorToken = token
+}
+
+// out is where the client prints its messages, the interactive terminal replaces it to keep the input line intact
+var out io.Writer = os.Stdout
+
 func (c *client) checkState() bool {
 	if !c.isConnected {
-		fmt.Println("You are not connected to a game session, join a server first")
+		fmt.Fprintln(out, "You are not connected to a game session, join a server first")
 	}
 
 	return c.isConnected
 }
 
-// TODO: add empty string check for name
-func (c *client) Connect(clientName, address string) {
+// dial opens a connection to the server, it is closed if the client doesn't manage to join a room
+func (c *client) dial(address string) bool {
 	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		log.Printf("Couldn't connect to grpc server: %v\n", err)
-		return
+		return false
 	}
 
 	c.conn = conn
 	c.dialer = proto.NewMafiaClient(conn)
+	return true
+}
+
+// TODO: add empty string check for name
+// Connect joins the room with the invite code, or the main room if the code is empty
+func (c *client) Connect(clientName, address, room, password string) {
+	if c.dial(address) {
+		c.join(clientName, room, password)
+	}
+}
+
+// CreateRoom opens a new room on the server and joins it, the first player becomes the host
+func (c *client) CreateRoom(clientName, address string, req *proto.RoomReq) {
+	if !c.dial(address) {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	assignedId, err := cl.dialer.Connect(ctx, &proto.ClientInfo{Name: clientName})
+	info, err := c.dialer.CreateRoom(ctx, req)
+	if err != nil {
+		if err := c.conn.Close(); err != nil {
+		}
+		log.Printf("Couldn't create a room: %s\n", status.Convert(err).Message())
+		return
+	}
+	fmt.Fprintf(out, "Room %s has been created, share the code with other players\n", info.Code)
+	c.join(clientName, info.Code, req.Password)
+}
+
+// ShowRooms lists the public rooms of the server the client is connected to or of the given one
+func (c *client) ShowRooms(address string) {
+	if !c.isConnected {
+		if address == "" {
+			fmt.Fprintln(out, "Provide the server's address to list its rooms")
+			return
+		}
+		if !c.dial(address) {
+			return
+		}
+		defer c.conn.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	list, err := c.dialer.ListRooms(ctx, &proto.EmptyMsg{})
+	if err != nil {
+		log.Printf("Couldn't get the rooms: %s\n", status.Convert(err).Message())
+		return
+	}
+	fmt.Fprintln(out, formatRooms(list.Rooms))
+}
+
+// join enters a room over the open connection
+func (c *client) join(clientName, room, password string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assignedId, err := cl.dialer.Connect(ctx, &proto.ClientInfo{Name: clientName, Room: room, Password: password, ModeratorToken: c.moderatorToken})
 	if err != nil {
 		if err := cl.conn.Close(); err != nil {
 		}
@@ -54,6 +124,7 @@ func (c *client) Connect(clientName, address string) {
 	}
 	c.id = assignedId.Id
 	c.isConnected = true
+	c.lastSequence = 0
 }
 
 func (c *client) Disconnect() {
@@ -75,7 +146,23 @@ func (c *client) Disconnect() {
 	c.isConnected = false
 }
 
-func (c *client) Chat(msg string) {
+// Chat sends the message to the channel, an empty channel lets the server pick one by the phase and your role
+func (c *client) Chat(channel, recipient, msg string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.dialer.Chat(ctx, &proto.ChatMsg{Id: &proto.ClientId{Id: c.id}, Msg: msg, Channel: channel, Recipient: recipient})
+	if err != nil {
+		log.Printf("Message rejected: %s\n", status.Convert(err).Message())
+	}
+}
+
+// SetMuted mutes or unmutes the player in chat if you are a moderator
+func (c *client) SetMuted(target string, muted bool) {
 	if !c.checkState() {
 		return
 	}
@@ -83,13 +170,143 @@ func (c *client) Chat(msg string) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := c.dialer.Chat(ctx, &proto.ChatMsg{Id: &proto.ClientId{Id: c.id}, Msg: msg})
+	req := &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}}
+	var err error
+	if muted {
+		_, err = c.dialer.Mute(ctx, req)
+	} else {
+		_, err = c.dialer.Unmute(ctx, req)
+	}
+	if err != nil {
+		log.Printf("Couldn't change mute status: %s\n", status.Convert(err).Message())
+	}
+}
+
+func (c *client) ShowChatHistory(channel string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	history, err := c.dialer.GetChatHistory(ctx, &proto.ChatHistoryReq{Id: &proto.ClientId{Id: c.id}, Channel: channel})
 	if err != nil {
 		log.Printf("Couldn't get response from server: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(out, "Chat history:")
+	for _, entry := range history.Messages {
+		fmt.Fprintln(out, formatChatEntry(entry))
 	}
 }
 
-func (c *client) Subscribe() {
+// ShowGameState prints what is happening in the session right now
+func (c *client) ShowGameState() {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	state, err := c.dialer.GetGameState(ctx, &proto.ClientId{Id: c.id})
+	if err != nil {
+		log.Printf("Couldn't get response from server: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(out, formatGameState(state))
+}
+
+// ShowTeam prints the allies the player knows of
+func (c *client) ShowTeam() {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	team, err := c.dialer.GetTeam(ctx, &proto.ClientId{Id: c.id})
+	if err != nil {
+		log.Printf("Couldn't get the team: %s\n", status.Convert(err).Message())
+		return
+	}
+
+	fmt.Fprintln(out, formatTeam(team))
+}
+
+// ShowInvestigations prints the night checks the player knows of
+func (c *client) ShowInvestigations() {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	checks, err := c.dialer.GetInvestigations(ctx, &proto.ClientId{Id: c.id})
+	if err != nil {
+		log.Printf("Couldn't get the checks: %s\n", status.Convert(err).Message())
+		return
+	}
+
+	fmt.Fprintln(out, formatInvestigations(checks))
+}
+
+// ShowVoteTally prints the running count of the day votes
+func (c *client) ShowVoteTally() {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tally, err := c.dialer.GetVoteTally(ctx, &proto.ClientId{Id: c.id})
+	if err != nil {
+		log.Printf("Couldn't get votes: %s\n", status.Convert(err).Message())
+		return
+	}
+
+	if len(tally.Tally) == 0 && len(tally.Abstainers) == 0 {
+		fmt.Fprintln(out, "Nobody has voted yet")
+		return
+	}
+	fmt.Fprintln(out, "Votes:")
+	fmt.Fprintln(out, formatTally(tally.Tally, tally.Abstainers))
+}
+
+// ShowReport prints the report of a finished game or saves it to the file if one is given
+func (c *client) ShowReport(gameId, format, file string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	report, err := c.dialer.GetGameReport(ctx, &proto.ReportReq{GameId: gameId, Format: format, Id: &proto.ClientId{Id: c.id}})
+	if err != nil {
+		log.Printf("Couldn't get the report: %s\n", status.Convert(err).Message())
+		return
+	}
+
+	if file == "" {
+		fmt.Fprintln(out, report.Content)
+		return
+	}
+	if err := os.WriteFile(file, []byte(report.Content+"\n"), 0644); err != nil {
+		log.Printf("Couldn't save the report: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "The report of the game %s has been saved to %s\n", report.GameId, file)
+}
+
+// Subscribe receives server notifications until the game ends or the stream breaks, passing each one to the handler
+func (c *client) Subscribe(handler func(*proto.Notification)) {
 	if !c.checkState() {
 		return
 	}
@@ -97,7 +314,7 @@ func (c *client) Subscribe() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	stream, err := c.dialer.SubscribeToNotifications(ctx, &proto.ClientId{Id: c.id})
+	stream, err := c.dialer.SubscribeToNotifications(ctx, &proto.SubscribeReq{Id: c.id, AfterSequence: c.lastSequence})
 	if err != nil {
 		log.Println("Subscription Failed")
 		cl.Disconnect()
@@ -109,11 +326,19 @@ func (c *client) Subscribe() {
 		if err == io.EOF {
 			break
 		}
+		if status.Code(err) == codes.ResourceExhausted {
+			// the server dropped us as a slow consumer, the rest is kept in the history so just subscribe again
+			log.Printf("%s, resubscribing\n", status.Convert(err).Message())
+			if stream, err = c.dialer.SubscribeToNotifications(ctx, &proto.SubscribeReq{Id: c.id, AfterSequence: c.lastSequence}); err == nil {
+				continue
+			}
+		}
 		if err != nil {
 			log.Println("Stopped receiving notifications from server, try reconnecting")
 			break
 		}
-		log.Printf(notification.Info)
+		c.lastSequence = notification.Sequence
+		handler(notification)
 		if len(notification.Info) > 12 && notification.Info[:12] == "The outcome" {
 			break
 		}
@@ -124,26 +349,85 @@ func (c *client) Subscribe() {
 	}
 }
 
+func (c *client) getPlayers() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := c.dialer.ShowPlayersList(ctx, &proto.ClientId{Id: c.id})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Players, nil
+}
+
 func (c *client) ShowPlayersList() {
 	if !c.checkState() {
 		return
 	}
 
+	players, err := c.getPlayers()
+	if err != nil {
+		log.Printf("Couldn't get response from server: %v\n", err)
+		return
+	}
+
+	fmt.Fprintln(out, "Players in session:")
+	for _, name := range players {
+		fmt.Fprintln(out, name)
+	}
+}
+
+func (c *client) Vote(target string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.dialer.Vote(ctx, &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}})
+	if err != nil {
+		log.Printf("Couldn't get response from server: %v\n", err)
+	}
+}
+
+func (c *client) Abstain() {
+	if !c.checkState() {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	resp, err := c.dialer.ShowPlayersList(ctx, &proto.EmptyMsg{})
+	_, err := c.dialer.Abstain(ctx, &proto.ClientId{Id: c.id})
 	if err != nil {
 		log.Printf("Couldn't get response from server: %v\n", err)
 	}
+}
 
-	fmt.Println("Players in session:")
-	for _, name := range resp.Players {
-		fmt.Println(name)
+// Ready tells the host whether the player is ready for the next game
+func (c *client) Ready(ready bool) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var err error
+	if ready {
+		_, err = c.dialer.Ready(ctx, &proto.ClientId{Id: c.id})
+	} else {
+		_, err = c.dialer.Unready(ctx, &proto.ClientId{Id: c.id})
+	}
+	if err != nil {
+		log.Printf("Couldn't change readiness: %s\n", status.Convert(err).Message())
 	}
 }
 
-func (c *client) Vote(target string) {
+// StartGame asks the server to start the countdown, only the host may do that
+func (c *client) StartGame() {
 	if !c.checkState() {
 		return
 	}
@@ -151,7 +435,101 @@ func (c *client) Vote(target string) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := c.dialer.Vote(ctx, &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}})
+	if _, err := c.dialer.StartGame(ctx, &proto.ClientId{Id: c.id}); err != nil {
+		log.Printf("Couldn't start the game: %s\n", status.Convert(err).Message())
+	}
+}
+
+// Kick removes the player from the lobby, only the host may do that
+func (c *client) Kick(target string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req := &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}}
+	if _, err := c.dialer.Kick(ctx, req); err != nil {
+		log.Printf("Couldn't kick the player: %s\n", status.Convert(err).Message())
+	}
+}
+
+// VoteKick votes to kick the player from the lobby, or to turn them into a ghost during the day
+func (c *client) VoteKick(target string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req := &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}}
+	if _, err := c.dialer.VoteKick(ctx, req); err != nil {
+		log.Printf("Couldn't vote to kick the player: %s\n", status.Convert(err).Message())
+	}
+}
+
+// SetRolePreference asks for better odds of the preferred role and worse of the avoided one, "any" means no wish
+func (c *client) SetRolePreference(prefer, avoid string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if prefer == "any" {
+		prefer = ""
+	}
+	if avoid == "any" {
+		avoid = ""
+	}
+	req := &proto.RolePreferenceReq{Id: &proto.ClientId{Id: c.id}, Prefer: prefer, Avoid: avoid}
+	if _, err := c.dialer.SetRolePreference(ctx, req); err != nil {
+		log.Printf("Couldn't set the role preference: %s\n", status.Convert(err).Message())
+		return
+	}
+	log.Printf("Role wishes: %s\n", formatPreference(prefer, avoid))
+}
+
+func (c *client) Check(target string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.dialer.Check(ctx, &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}})
+	if err != nil {
+		log.Printf("Couldn't get response from server: %v\n", err)
+	}
+}
+
+func (c *client) Nominate(target string) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.dialer.Nominate(ctx, &proto.ClientReq{Id: &proto.ClientId{Id: c.id}, Target: &proto.ClientInfo{Name: target}})
+	if err != nil {
+		log.Printf("Couldn't get response from server: %v\n", err)
+	}
+}
+
+func (c *client) Verdict(guilty bool) {
+	if !c.checkState() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := c.dialer.Verdict(ctx, &proto.VerdictReq{Id: &proto.ClientId{Id: c.id}, Guilty: guilty})
 	if err != nil {
 		log.Printf("Couldn't get response from server: %v\n", err)
 	}
@@ -171,7 +549,8 @@ func (c *client) EndDay() {
 	}
 }
 
-func (c *client) Expose() {
+// Expose reveals the finding about the checked player to everyone, the latest found mafia member if no name is given
+func (c *client) Expose(target string) {
 	if !c.checkState() {
 		return
 	}
@@ -179,80 +558,262 @@ func (c *client) Expose() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := c.dialer.Expose(ctx, &proto.ClientId{Id: c.id})
+	_, err := c.dialer.Expose(ctx, &proto.ExposeReq{Id: c.id, Target: target})
 	if err != nil {
 		log.Printf("Couldn't get response from server: %v\n", err)
 	}
 }
 
-func Run() {
-	defer cl.Disconnect()
-
-	fmt.Println("----\tYou have launched Mafia client\t----\nprint 'help' for the list of available commands")
-	for reader := bufio.NewReader(os.Stdin); ; {
-		cmd, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Println("Error reading string", err)
-			continue
+// execute runs a single command line, asking for the missing arguments, and reports whether the client should keep running
+func (c *client) execute(line string, ask func(prompt string) (string, error)) bool {
+	cmd, args := parseCommand(line)
+	switch cmd {
+	case CONNECT, CREATE_ROOM:
+		if c.isConnected {
+			fmt.Fprintln(out, "You are already in the game session")
+			break
 		}
 
-		switch parseCommand(strings.TrimSpace(cmd)) {
-		case CONNECT:
-			if cl.isConnected {
-				fmt.Println("You are already in the game session")
+		// the optional arguments are empty unless given
+		fields := append(strings.Fields(args), make([]string, 6)...)
+		serverAddr, name := fields[0], fields[1]
+		var err error
+		if serverAddr == "" {
+			if serverAddr, err = ask("Enter server's address:"); err != nil {
+				fmt.Fprintln(out, "Error parsing server address", err)
 				break
 			}
-
-			fmt.Println("Enter server's address:")
-			serverAddr, err := reader.ReadString('\n')
-			if err != nil {
-				fmt.Println("Error parsing server address", err)
-				continue
+		}
+		if name == "" {
+			if name, err = ask("Enter your nickname:"); err != nil {
+				fmt.Fprintln(out, "Error parsing nickname", err)
+				break
 			}
+		}
 
-			fmt.Println("Enter your nickname:")
-			name, err := reader.ReadString('\n')
-			if err != nil {
-				fmt.Println("Error parsing nickname", err)
-				continue
+		if cmd == CONNECT {
+			c.Connect(name, serverAddr, fields[2], fields[3])
+		} else {
+			var maxPlayers uint64
+			if fields[3] != "" {
+				if maxPlayers, err = strconv.ParseUint(fields[3], 10, 32); err != nil {
+					fmt.Fprintln(out, "Error parsing max players", err)
+					break
+				}
 			}
-
-			cl.Connect(strings.TrimSpace(name), strings.TrimSpace(serverAddr))
-			go cl.Subscribe()
-		case DISCONNECT:
-			cl.Disconnect()
-		case SHOW_PLAYER_LIST:
-			cl.ShowPlayersList()
-		case VOTE:
-			fmt.Println("Enter a player's name:")
-			target, err := reader.ReadString('\n')
-			if err != nil {
-				fmt.Println("Error parsing player's name to vote", err)
-				continue
+			c.CreateRoom(name, serverAddr, &proto.RoomReq{Visibility: fields[2], MaxPlayers: uint32(maxPlayers), Ruleset: fields[4], Password: fields[5]})
+		}
+		if !c.isConnected {
+			break
+		}
+		go c.Subscribe(func(notification *proto.Notification) {
+			log.Printf(notification.Info)
+			if notification.Event == "GAME_REPORT" {
+				c.ShowReport(notification.Data, "", "")
 			}
-			cl.Vote(strings.TrimSpace(target))
-		case END_DAY:
-			cl.EndDay()
-		case EXPOSE:
-			cl.Expose()
-		case EXIT:
-			fmt.Println("Bye-bye!")
-			cl.Disconnect()
-			os.Exit(0)
-		case CHAT:
-			fmt.Println("Enter your message:")
-			msg, err := reader.ReadString('\n')
-			if err != nil {
-				fmt.Println("Error reading message", err)
-				continue
+		})
+	case ROOMS:
+		c.ShowRooms(args)
+	case DISCONNECT:
+		c.Disconnect()
+	case SHOW_PLAYER_LIST:
+		c.ShowPlayersList()
+	case VOTE:
+		target := args
+		if target == "" {
+			var err error
+			if target, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name to vote", err)
+				break
+			}
+		}
+		c.Vote(target)
+	case ABSTAIN:
+		c.Abstain()
+	case READY, UNREADY:
+		c.Ready(cmd == READY)
+	case START:
+		c.StartGame()
+	case KICK, VOTE_KICK:
+		target := args
+		if target == "" {
+			var err error
+			if target, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name", err)
+				break
+			}
+		}
+		if cmd == KICK {
+			c.Kick(target)
+		} else {
+			c.VoteKick(target)
+		}
+	case PREFER:
+		prefer, avoid, _ := strings.Cut(args, " ")
+		c.SetRolePreference(strings.ToLower(prefer), strings.ToLower(strings.TrimSpace(avoid)))
+	case NOMINATE:
+		target := args
+		if target == "" {
+			var err error
+			if target, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name to nominate", err)
+				break
+			}
+		}
+		c.Nominate(target)
+	case CHECK:
+		target := args
+		if target == "" {
+			var err error
+			if target, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name to check", err)
+				break
+			}
+		}
+		c.Check(target)
+	case GUILTY, INNOCENT:
+		c.Verdict(cmd == GUILTY)
+	case END_DAY:
+		c.EndDay()
+	case EXPOSE:
+		c.Expose(args)
+	case CHECKS:
+		c.ShowInvestigations()
+	case EXIT:
+		fmt.Fprintln(out, "Bye-bye!")
+		return false
+	case CHAT:
+		// the channel may be given as the first word, e.g. 'chat #mafia hi'
+		channel, msg := "", args
+		if strings.HasPrefix(args, "#") {
+			channel, msg, _ = strings.Cut(strings.TrimPrefix(args, "#"), " ")
+			msg = strings.TrimSpace(msg)
+		}
+		if msg == "" {
+			var err error
+			if msg, err = ask("Enter your message:"); err != nil {
+				fmt.Fprintln(out, "Error reading message", err)
+				break
 			}
+		}
+		c.Chat(channel, "", msg)
+	case DIRECT_MSG:
+		recipient, msg, _ := strings.Cut(args, " ")
+		msg = strings.TrimSpace(msg)
+		var err error
+		if recipient == "" {
+			if recipient, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name", err)
+				break
+			}
+		}
+		if msg == "" {
+			if msg, err = ask("Enter your message:"); err != nil {
+				fmt.Fprintln(out, "Error reading message", err)
+				break
+			}
+		}
+		c.Chat(DIRECT_CHANNEL, recipient, msg)
+	case MUTE, UNMUTE:
+		target := args
+		if target == "" {
+			var err error
+			if target, err = ask("Enter a player's name:"); err != nil {
+				fmt.Fprintln(out, "Error parsing player's name", err)
+				break
+			}
+		}
+		c.SetMuted(target, cmd == MUTE)
+	case CHAT_HISTORY:
+		c.ShowChatHistory(strings.TrimPrefix(args, "#"))
+	case GAME_STATE:
+		c.ShowGameState()
+	case TEAM:
+		c.ShowTeam()
+	case VOTE_TALLY:
+		c.ShowVoteTally()
+	case REPORT:
+		// report [game id|last] [format] [file]
+		fields := strings.Fields(args)
+		for len(fields) < 3 {
+			fields = append(fields, "")
+		}
+		if fields[0] == "last" {
+			fields[0] = ""
+		}
+		c.ShowReport(fields[0], fields[1], fields[2])
+	case WAIT:
+		seconds, err := strconv.ParseFloat(args, 64)
+		if err != nil {
+			fmt.Fprintln(out, "Error parsing number of seconds to wait", err)
+			break
+		}
+		time.Sleep(time.Duration(seconds * float64(time.Second)))
+	case HELP:
+		showHints()
+	case UNKNOWN:
+		fmt.Fprintln(out, "Unknown command, print 'help' to see available commands")
+	}
+
+	return true
+}
+
+// runScript executes commands separated by ';' and then the ones from the script file, line by line
+func runScript(commands, scriptPath string) {
+	lines := strings.Split(commands, ";")
+	if scriptPath != "" {
+		script, err := os.ReadFile(scriptPath)
+		if err != nil {
+			log.Printf("Couldn't read script: %v\n", err)
+			return
+		}
+		lines = append(lines, strings.Split(string(script), "\n")...)
+	}
+
+	noPrompt := func(prompt string) (string, error) {
+		return "", missingArgumentError
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fmt.Fprintln(out, ">", line)
+		if !cl.execute(line, noPrompt) {
+			return
+		}
+	}
+}
+
+// Run starts the client, it executes the given commands or script non-interactively if any are provided
+func Run(commands, scriptPath string) {
+	defer cl.Disconnect()
+
+	if commands != "" || scriptPath != "" {
+		runScript(commands, scriptPath)
+		return
+	}
+
+	input := newInputReader()
+	defer input.Close()
 
-			cl.Chat(strings.TrimSpace(msg))
-		case HELP:
-			showHints()
-		case UNKNOWN:
-			fmt.Println("Unknown command, print 'help' to see available commands")
+	fmt.Fprintln(out, "----\tYou have launched Mafia client\t----\nprint 'help' for the list of available commands")
+	for {
+		line, err := input.ReadLine()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(out, "Error reading string", err)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
 		}
 
+		if !cl.execute(line, input.Ask) {
+			return
+		}
 	}
 }
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFailedConnectDoesNotSubscribe(t *testing.T) {
+	var buf bytes.Buffer
+	out = &buf
+	log.SetOutput(io.Discard)
+	defer func() {
+		out = os.Stdout
+		log.SetOutput(os.Stderr)
+	}()
+
+	// nothing listens on the port, so the connection is refused
+	if !cl.execute("connect 127.0.0.1:1 bob", nil) {
+		t.Fatalf("the client has stopped after a failed connect")
+	}
+	// a subscription would complain about the missing session right away
+	time.Sleep(50 * time.Millisecond)
+	if cl.isConnected {
+		t.Errorf("the client is connected without a server")
+	}
+	if strings.Contains(buf.String(), "not connected") {
+		t.Errorf("the client has subscribed after a failed connect: %q", buf.String())
+	}
+}
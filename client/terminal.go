@@ -0,0 +1,158 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const PROMPT = "> "
+
+var missingArgumentError = errors.New("the command requires an argument")
+
+// inputReader reads commands of the interactive client
+type inputReader interface {
+	ReadLine() (string, error)
+	// Ask shows the prompt and reads an extra line for the command being executed
+	Ask(prompt string) (string, error)
+	Close()
+}
+
+// plainReader is used when the input is not a terminal, e.g. a pipe
+type plainReader struct {
+	reader *bufio.Reader
+}
+
+func (r *plainReader) ReadLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		return strings.TrimSpace(line), nil
+	}
+
+	return strings.TrimSpace(line), err
+}
+
+func (r *plainReader) Ask(prompt string) (string, error) {
+	fmt.Fprintln(out, prompt)
+	return r.ReadLine()
+}
+
+func (r *plainReader) Close() {}
+
+// terminalReader provides line editing, command history and tab completion
+type terminalReader struct {
+	terminal *term.Terminal
+	oldState *term.State
+}
+
+func (r *terminalReader) ReadLine() (string, error) {
+	line, err := r.terminal.ReadLine()
+	return strings.TrimSpace(line), err
+}
+
+func (r *terminalReader) Ask(prompt string) (string, error) {
+	r.terminal.SetPrompt(prompt + " ")
+	defer r.terminal.SetPrompt(PROMPT)
+	return r.ReadLine()
+}
+
+func (r *terminalReader) Close() {
+	out = os.Stdout
+	log.SetOutput(os.Stderr)
+	if err := term.Restore(int(os.Stdin.Fd()), r.oldState); err != nil {
+		log.Printf("Couldn't restore terminal: %v\n", err)
+	}
+}
+
+func newInputReader() inputReader {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return &plainReader{reader: bufio.NewReader(os.Stdin)}
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		log.Printf("Couldn't switch terminal to raw mode: %v\n", err)
+		return &plainReader{reader: bufio.NewReader(os.Stdin)}
+	}
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, PROMPT)
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		if err := terminal.SetSize(width, height); err != nil {
+			log.Printf("Couldn't set terminal size: %v\n", err)
+		}
+	}
+	terminal.AutoCompleteCallback = cl.complete
+
+	// notifications are printed above the input line instead of breaking it
+	out = terminal
+	log.SetOutput(terminal)
+	return &terminalReader{terminal: terminal, oldState: oldState}
+}
+
+// complete expands command names and, for commands targeting a player, names of players in the session on Tab
+func (c *client) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head, tail := line[:pos], line[pos:]
+	start := strings.LastIndex(head, " ") + 1
+	word := head[start:]
+
+	var candidates []string
+	if start == 0 {
+		for _, cmd := range commands {
+			candidates = append(candidates, cmd.toString())
+		}
+	} else if cmd, _ := parseCommand(head); (cmd == VOTE || cmd == NOMINATE || cmd == CHECK || cmd == DIRECT_MSG || cmd == MUTE || cmd == UNMUTE || cmd == KICK || cmd == VOTE_KICK) && c.isConnected {
+		players, err := c.getPlayers()
+		if err != nil {
+			return "", 0, false
+		}
+		candidates = players
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if candidate != "" && strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", 0, false
+	case 1:
+		completed := head[:start] + matches[0] + " "
+		return completed + tail, len(completed), true
+	}
+
+	if prefix := commonPrefix(matches); len(prefix) > len(word) {
+		completed := head[:start] + prefix
+		return completed + tail, len(completed), true
+	}
+
+	fmt.Fprintln(out, strings.Join(matches, "  "))
+	return line, pos, true
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
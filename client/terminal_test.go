@@ -0,0 +1,54 @@
+package client
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestCommonPrefix(t *testing.T) {
+	for _, test := range []struct {
+		words  []string
+		prefix string
+	}{
+		{[]string{"alice"}, "alice"},
+		{[]string{"p1", "p10", "p2"}, "p"},
+		{[]string{"vote", "votekick", "votes"}, "vote"},
+		{[]string{"bob", "alice"}, ""},
+	} {
+		if prefix := commonPrefix(test.words); prefix != test.prefix {
+			t.Errorf("the common prefix of %v is %q instead of %q", test.words, prefix, test.prefix)
+		}
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	out = io.Discard
+	defer func() { out = os.Stdout }()
+
+	c := &client{}
+	for _, test := range []struct {
+		line string
+		pos  int
+		res  string
+		at   int
+		ok   bool
+	}{
+		{"dis", 3, "disconnect ", 11, true},
+		{"vo", 2, "vote", 4, true},
+		{"che x", 3, "check x", 5, true},
+		// ambiguous without a longer prefix: the matches are listed and the line is kept
+		{"vote", 4, "vote", 4, true},
+		{"xyz", 3, "", 0, false},
+		// player names need a session
+		{"vote b", 6, "", 0, false},
+	} {
+		res, at, ok := c.complete(test.line, test.pos, '\t')
+		if res != test.res || at != test.at || ok != test.ok {
+			t.Errorf("%q at %d is completed as %q at %d, %v instead of %q at %d, %v", test.line, test.pos, res, at, ok, test.res, test.at, test.ok)
+		}
+	}
+	if _, _, ok := c.complete("dis", 3, 'a'); ok {
+		t.Errorf("only Tab should complete")
+	}
+}
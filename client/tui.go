@@ -0,0 +1,509 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mafia-core/proto"
+	"os"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+// ---- tui views
+const (
+	STATUS_VIEW  = "status"
+	PLAYERS_VIEW = "players"
+	EVENTS_VIEW  = "events"
+	CHAT_VIEW    = "chat"
+	INPUT_VIEW   = "input"
+)
+
+const tuiHints = "Tab: switch pane | ↑/↓: select player | Enter/F2: vote | a: abstain | n: nominate | c: check | F6/F7: guilty/innocent | s/F3: skip | e/F4: expose | F5: refresh | Ctrl-C: quit"
+
+// tui keeps what the player currently knows about the game session
+type tui struct {
+	gui     *gocui.Gui
+	name    string
+	role    string
+	phase   string
+	round   int
+	players []string
+	dead    map[string]bool
+	// team holds the roles of the known teammates by name
+	team     map[string]string
+	votes    map[string]string
+	selected int
+}
+
+// viewWriter redirects log output of the client into one of the tui views
+type viewWriter struct {
+	gui  *gocui.Gui
+	view string
+}
+
+func (w viewWriter) Write(p []byte) (int, error) {
+	msg := string(p)
+	w.gui.Update(func(g *gocui.Gui) error {
+		v, err := g.View(w.view)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(v, msg)
+		return nil
+	})
+
+	return len(p), nil
+}
+
+func (t *tui) layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	playersX, eventsX := maxX/4, maxX*5/8
+
+	if v, err := g.SetView(STATUS_VIEW, 0, 0, maxX-1, 2); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = true
+	}
+
+	if v, err := g.SetView(PLAYERS_VIEW, 0, 3, playersX, maxY-4); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Players"
+		v.Highlight = true
+		v.SelBgColor = gocui.ColorGreen
+		v.SelFgColor = gocui.ColorBlack
+	}
+
+	if v, err := g.SetView(EVENTS_VIEW, playersX+1, 3, eventsX, maxY-4); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Events"
+		v.Wrap = true
+		v.Autoscroll = true
+	}
+
+	if v, err := g.SetView(CHAT_VIEW, eventsX+1, 3, maxX-1, maxY-4); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Wrap = true
+		v.Autoscroll = true
+	}
+
+	if v, err := g.SetView(INPUT_VIEW, 0, maxY-3, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Message (Enter to send, /p public, /m mafia, /g ghosts, /l lovers, /w <player> direct)"
+		v.Editable = true
+		if _, err := g.SetCurrentView(INPUT_VIEW); err != nil {
+			return err
+		}
+	}
+
+	return t.render(g)
+}
+
+// render redraws the views that depend on the game state
+func (t *tui) render(g *gocui.Gui) error {
+	if v, err := g.View(STATUS_VIEW); err == nil {
+		v.Clear()
+		phase := t.phase
+		if t.round > 0 {
+			phase = fmt.Sprintf("%s %d", t.phase, t.round)
+		}
+		role := t.role
+		if role == "" {
+			role = "-"
+		}
+		fmt.Fprintf(v, " %s | phase: %s | role: %s | %s", t.name, phase, role, tuiHints)
+	}
+
+	if v, err := g.View(PLAYERS_VIEW); err == nil {
+		v.Clear()
+		counts := make(map[string]int)
+		for _, target := range t.votes {
+			counts[target]++
+		}
+		for _, name := range t.players {
+			mark := "  "
+			if t.dead[name] {
+				mark = "x "
+			}
+			line := mark + name
+			if name == t.name {
+				line += " (you)"
+			} else if role, ok := t.team[name]; ok {
+				line += " <" + role + ">"
+			}
+			if counts[name] > 0 {
+				line += fmt.Sprintf(" [%d]", counts[name])
+			}
+			fmt.Fprintln(v, line)
+		}
+		if t.selected >= len(t.players) {
+			t.selected = len(t.players) - 1
+		}
+		if t.selected < 0 {
+			t.selected = 0
+		}
+		if err := v.SetCursor(0, t.selected); err != nil {
+			return err
+		}
+	}
+
+	if v, err := g.View(CHAT_VIEW); err == nil {
+		switch {
+		case t.role == "ghost":
+			v.Title = "Chat (ghosts)"
+		case t.phase == "night":
+			v.Title = "Chat (mafia)"
+		default:
+			v.Title = "Chat (public)"
+		}
+	}
+
+	return nil
+}
+
+func (t *tui) refreshPlayers() {
+	players, err := cl.getPlayers()
+	if err != nil {
+		log.Printf("Couldn't get players list: %v\n", err)
+		return
+	}
+
+	t.gui.Update(func(g *gocui.Gui) error {
+		t.players = t.players[:0]
+		for _, name := range players {
+			if name != "" {
+				t.players = append(t.players, name)
+			}
+		}
+		return t.render(g)
+	})
+}
+
+func (t *tui) onNotification(notification *proto.Notification) {
+	t.gui.Update(func(g *gocui.Gui) error {
+		switch notification.Event {
+		case "CHAT_MSG":
+			v, err := g.View(CHAT_VIEW)
+			if err != nil {
+				return err
+			}
+			msg := strings.SplitN(notification.Data, "@@", 4)
+			if len(msg) == 4 {
+				fmt.Fprintln(v, formatChatEntry(&proto.ChatEntry{Channel: msg[0], Sender: msg[1], Recipient: msg[2], Msg: msg[3]}))
+			}
+			return nil
+		case "ROLE_ASSIGNED":
+			parts := strings.Split(notification.Data, "@@")
+			t.role = parts[0]
+			t.team = make(map[string]string)
+			for _, mate := range parts[1:] {
+				name, role, _ := strings.Cut(mate, " ")
+				t.team[name] = role
+			}
+		case "SESSION_START":
+			t.dead = make(map[string]bool)
+			t.round = 0
+		case "SESSION_END":
+			t.phase = "ended"
+		case "PHASE_START_DAY":
+			t.phase = "day"
+			t.round++
+			t.votes = make(map[string]string)
+		case "PHASE_START_NIGHT":
+			t.phase = "night"
+			t.votes = make(map[string]string)
+		case "VOTE_CAST", "NOMINATION_MADE":
+			vote := strings.SplitN(notification.Data, "@@", 3)
+			if len(vote) >= 2 {
+				t.votes[vote[0]] = vote[1]
+			}
+		case "VOTE_ABSTAINED":
+			delete(t.votes, notification.Data)
+		case "REVOTE_START":
+			t.votes = make(map[string]string)
+		case "PLAYER_ELIMINATED":
+			name := strings.Split(notification.Data, " ")[0]
+			t.dead[name] = true
+			// the votes against a player removed during the day are withdrawn
+			for voter, target := range t.votes {
+				if target == name {
+					delete(t.votes, voter)
+				}
+			}
+			if name == t.name {
+				t.role = "ghost"
+			}
+		}
+
+		v, err := g.View(EVENTS_VIEW)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(v, notification.Info)
+		return t.render(g)
+	})
+
+	switch notification.Event {
+	case "CLIENT_CONNECTED", "CLIENT_DISCONNECTED", "SESSION_START":
+		go t.refreshPlayers()
+	}
+}
+
+func (t *tui) selectedPlayer() (string, bool) {
+	if t.selected < 0 || t.selected >= len(t.players) {
+		return "", false
+	}
+
+	return t.players[t.selected], true
+}
+
+func (t *tui) keybindings(g *gocui.Gui) error {
+	bindings := []struct {
+		view    string
+		key     interface{}
+		handler func(*gocui.Gui, *gocui.View) error
+	}{
+		{"", gocui.KeyCtrlC, t.quit},
+		{"", gocui.KeyTab, t.switchPane},
+		{"", gocui.KeyF2, t.vote},
+		{"", gocui.KeyF3, t.skip},
+		{"", gocui.KeyF4, t.expose},
+		{"", gocui.KeyF5, t.refresh},
+		{"", gocui.KeyF6, t.verdict(true)},
+		{"", gocui.KeyF7, t.verdict(false)},
+		{PLAYERS_VIEW, gocui.KeyArrowUp, t.moveSelection(-1)},
+		{PLAYERS_VIEW, gocui.KeyArrowDown, t.moveSelection(1)},
+		{PLAYERS_VIEW, gocui.KeyEnter, t.vote},
+		{PLAYERS_VIEW, 'v', t.vote},
+		{PLAYERS_VIEW, 'n', t.nominate},
+		{PLAYERS_VIEW, 'a', t.abstain},
+		{PLAYERS_VIEW, 'c', t.check},
+		{PLAYERS_VIEW, 's', t.skip},
+		{PLAYERS_VIEW, 'e', t.expose},
+		{PLAYERS_VIEW, 'r', t.ready(true)},
+		{PLAYERS_VIEW, 'u', t.ready(false)},
+		{PLAYERS_VIEW, 'k', t.kick},
+		{PLAYERS_VIEW, 'x', t.voteKick},
+		{"", gocui.KeyF8, t.start},
+		{INPUT_VIEW, gocui.KeyEnter, t.sendChat},
+	}
+
+	for _, b := range bindings {
+		if err := g.SetKeybinding(b.view, b.key, gocui.ModNone, b.handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *tui) quit(*gocui.Gui, *gocui.View) error {
+	return gocui.ErrQuit
+}
+
+func (t *tui) switchPane(g *gocui.Gui, v *gocui.View) error {
+	next := PLAYERS_VIEW
+	if v != nil && v.Name() == PLAYERS_VIEW {
+		next = INPUT_VIEW
+	}
+
+	_, err := g.SetCurrentView(next)
+	return err
+}
+
+func (t *tui) moveSelection(delta int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, _ *gocui.View) error {
+		if next := t.selected + delta; next >= 0 && next < len(t.players) {
+			t.selected = next
+		}
+		return t.render(g)
+	}
+}
+
+func (t *tui) vote(*gocui.Gui, *gocui.View) error {
+	if target, ok := t.selectedPlayer(); ok {
+		log.Printf("You voted for %s\n", target)
+		go cl.Vote(target)
+	}
+
+	return nil
+}
+
+func (t *tui) nominate(*gocui.Gui, *gocui.View) error {
+	if target, ok := t.selectedPlayer(); ok {
+		log.Printf("You nominated %s\n", target)
+		go cl.Nominate(target)
+	}
+
+	return nil
+}
+
+func (t *tui) check(*gocui.Gui, *gocui.View) error {
+	if target, ok := t.selectedPlayer(); ok {
+		log.Printf("You checked %s\n", target)
+		go cl.Check(target)
+	}
+
+	return nil
+}
+
+func (t *tui) abstain(*gocui.Gui, *gocui.View) error {
+	log.Println("You abstained from the vote")
+	go cl.Abstain()
+	return nil
+}
+
+func (t *tui) ready(ready bool) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		go cl.Ready(ready)
+		return nil
+	}
+}
+
+func (t *tui) start(*gocui.Gui, *gocui.View) error {
+	go cl.StartGame()
+	return nil
+}
+
+func (t *tui) kick(*gocui.Gui, *gocui.View) error {
+	if target, ok := t.selectedPlayer(); ok {
+		go cl.Kick(target)
+	}
+	return nil
+}
+
+func (t *tui) voteKick(*gocui.Gui, *gocui.View) error {
+	if target, ok := t.selectedPlayer(); ok {
+		go cl.VoteKick(target)
+	}
+	return nil
+}
+
+func (t *tui) verdict(guilty bool) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		go cl.Verdict(guilty)
+		return nil
+	}
+}
+
+func (t *tui) skip(*gocui.Gui, *gocui.View) error {
+	log.Println("You skipped the rest of the day")
+	go cl.EndDay()
+	return nil
+}
+
+func (t *tui) expose(*gocui.Gui, *gocui.View) error {
+	go cl.Expose("")
+	return nil
+}
+
+func (t *tui) refresh(*gocui.Gui, *gocui.View) error {
+	go t.refreshPlayers()
+	return nil
+}
+
+func (t *tui) sendChat(_ *gocui.Gui, v *gocui.View) error {
+	msg := strings.TrimSpace(v.Buffer())
+	v.Clear()
+	if err := v.SetCursor(0, 0); err != nil {
+		return err
+	}
+
+	// the channel is picked by a prefix: /p public, /m mafia, /g ghosts, /l lovers, /w <player> direct
+	channel, recipient := "", ""
+	if prefix, rest, _ := strings.Cut(msg, " "); strings.HasPrefix(prefix, "/") {
+		switch prefix {
+		case "/p":
+			channel = "public"
+		case "/m":
+			channel = "mafia"
+		case "/g":
+			channel = "ghosts"
+		case "/l":
+			channel = "lovers"
+		case "/w":
+			channel = DIRECT_CHANNEL
+			recipient, rest, _ = strings.Cut(rest, " ")
+		case "/mute", "/unmute":
+			go cl.SetMuted(strings.TrimSpace(rest), prefix == "/mute")
+			return nil
+		case "/kick":
+			go cl.Kick(strings.TrimSpace(rest))
+			return nil
+		case "/votekick":
+			go cl.VoteKick(strings.TrimSpace(rest))
+			return nil
+		case "/expose":
+			go cl.Expose(strings.TrimSpace(rest))
+			return nil
+		case "/prefer":
+			prefer, avoid, _ := strings.Cut(strings.TrimSpace(rest), " ")
+			go cl.SetRolePreference(strings.ToLower(prefer), strings.ToLower(strings.TrimSpace(avoid)))
+			return nil
+		default:
+			rest = msg
+		}
+		msg = strings.TrimSpace(rest)
+	}
+
+	if msg != "" {
+		go cl.Chat(channel, recipient, msg)
+	}
+	return nil
+}
+
+// RunTUI connects to the server and runs a full-screen client
+func RunTUI(address, name, room, password string) {
+	if name == "" {
+		log.Fatalln("Provide your nickname with --name to use the tui client")
+	}
+
+	cl.Connect(name, address, room, password)
+	if !cl.isConnected {
+		return
+	}
+	defer cl.Disconnect()
+
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Printf("Couldn't start tui: %v\n", err)
+		return
+	}
+
+	t := &tui{
+		gui:   g,
+		name:  name,
+		phase: "lobby",
+		dead:  make(map[string]bool),
+		votes: make(map[string]string),
+	}
+	g.Cursor = true
+	g.SetManagerFunc(t.layout)
+	if err := t.keybindings(g); err != nil {
+		g.Close()
+		log.Printf("Couldn't set tui keybindings: %v\n", err)
+		return
+	}
+
+	log.SetFlags(0)
+	log.SetOutput(viewWriter{gui: g, view: EVENTS_VIEW})
+	go t.refreshPlayers()
+	go cl.Subscribe(t.onNotification)
+
+	err = g.MainLoop()
+	g.Close()
+	log.SetOutput(os.Stderr)
+	if err != nil && !errors.Is(err, gocui.ErrQuit) {
+		log.Printf("tui error: %v\n", err)
+	}
+}
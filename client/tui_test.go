@@ -0,0 +1,15 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestSelectedPlayer(t *testing.T) {
+	ui := &tui{players: []string{"alice", "bob"}}
+	for selected, expected := range map[int]string{-1: "", 0: "alice", 1: "bob", 2: ""} {
+		ui.selected = selected
+		if name, ok := ui.selectedPlayer(); name != expected || ok != (expected != "") {
+			t.Errorf("selection %d gives %q, %v instead of %q", selected, name, ok, expected)
+		}
+	}
+}
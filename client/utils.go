@@ -2,7 +2,9 @@ package client
 
 import (
 	"fmt"
+	"mafia-core/proto"
 	"strings"
+	"time"
 )
 
 type command uint16
@@ -11,24 +13,113 @@ const (
 	HELP command = iota
 	EXIT
 	CONNECT
+	CREATE_ROOM
+	ROOMS
 	DISCONNECT
 	SHOW_PLAYER_LIST
 	VOTE
+	ABSTAIN
+	NOMINATE
+	GUILTY
+	INNOCENT
+	CHECK
 	END_DAY
 	EXPOSE
+	CHECKS
 	CHAT
+	DIRECT_MSG
+	CHAT_HISTORY
+	GAME_STATE
+	TEAM
+	VOTE_TALLY
+	REPORT
+	READY
+	UNREADY
+	START
+	KICK
+	VOTE_KICK
+	PREFER
+	MUTE
+	UNMUTE
+	WAIT
 	UNKNOWN
 )
 
+var commands = []command{HELP, EXIT, CONNECT, CREATE_ROOM, ROOMS, DISCONNECT, SHOW_PLAYER_LIST, VOTE, ABSTAIN, NOMINATE, GUILTY, INNOCENT, CHECK, END_DAY, EXPOSE, CHECKS, CHAT, DIRECT_MSG, CHAT_HISTORY, GAME_STATE, TEAM, VOTE_TALLY, REPORT, READY, UNREADY, START, KICK, VOTE_KICK, PREFER, MUTE, UNMUTE, WAIT}
+
+// ---- chat channels, same as on the server
+const (
+	PUBLIC_CHANNEL = "public"
+	DIRECT_CHANNEL = "direct"
+)
+
+// aliases are short names accepted along with the full command names
+var aliases = map[string]command{
+	"?":      HELP,
+	"h":      HELP,
+	"q":      EXIT,
+	"quit":   EXIT,
+	"c":      CONNECT,
+	"join":   CONNECT,
+	"new":    CREATE_ROOM,
+	"dc":     DISCONNECT,
+	"ls":     SHOW_PLAYER_LIST,
+	"who":    SHOW_PLAYER_LIST,
+	"v":      VOTE,
+	"a":      ABSTAIN,
+	"nom":    NOMINATE,
+	"vk":     VOTE_KICK,
+	"pref":   PREFER,
+	"g":      GUILTY,
+	"i":      INNOCENT,
+	"ck":     CHECK,
+	"s":      END_DAY,
+	"end":    END_DAY,
+	"e":      EXPOSE,
+	"inv":    CHECKS,
+	"say":    CHAT,
+	"t":      CHAT,
+	"pm":     DIRECT_MSG,
+	"msg":    DIRECT_MSG,
+	"hist":   CHAT_HISTORY,
+	"st":     GAME_STATE,
+	"allies": TEAM,
+	"tally":  VOTE_TALLY,
+	"rep":    REPORT,
+	"r":      READY,
+	"w":      WAIT,
+}
+
 func showHints() {
-	fmt.Println("",
-		"'connect':\t join a game server\n",
-		"'exit':\t exit client\n",
-		"'players':\t show players in the game session\n",
-		"'vote':\t vote for a player\n",
-		"'expose':\t expose mafia if you are a detective\n",
-		"'skip':\t end your turn in the current day\n",
-		"'chat':\t send a message in chat",
+	fmt.Fprintln(out, "",
+		"'connect [address nickname] [room code] [password]':\t join a room of a game server, the main one without a code (alias 'c', 'join')\n",
+		"'create [address nickname] [public|unlisted] [max players] [server|classic|trial|extended] [password]':\t create a room and join it as the host (alias 'new')\n",
+		"'rooms [address]':\t show the public rooms of the server\n",
+		"'disconnect':\t leave the game server (alias 'dc')\n",
+		"'exit':\t exit client (alias 'q', 'quit')\n",
+		"'players':\t show players in the game session (alias 'ls', 'who')\n",
+		"'vote [player]':\t vote for a player, Tab completes names (alias 'v')\n",
+		"'abstain':\t vote for nobody, unlike skip it doesn't end your day (alias 'a')\n",
+		"'nominate [player]':\t put a player on trial, if the game uses trial days (alias 'nom')\n",
+		"'guilty', 'innocent':\t vote on the verdict for the accused (alias 'g', 'i')\n",
+		"'check [player]':\t check a player at night if you are the detective or the Don (alias 'ck')\n",
+		"'expose [player]':\t reveal what you have found out about the player to everyone, the last found mafia member by default, if you are a detective (alias 'e')\n",
+		"'checks':\t show your night checks in this game, and the other detectives' ones if they are shared (alias 'inv')\n",
+		"'skip':\t end your turn in the current day (alias 's', 'end')\n",
+		"'chat [#channel] [message]':\t send a message in chat, channels are public, mafia, ghosts and lovers (alias 'say', 't')\n",
+		"'dm [player] [message]':\t send a direct message to a living player during the day (alias 'pm', 'msg')\n",
+		"'history [channel]':\t show chat messages of the current game (alias 'hist')\n",
+		"'state':\t show the phase, your role, who is alive and the current day votes (alias 'st')\n",
+		"'team':\t show the allies you know of in the current game (alias 'allies')\n",
+		"'votes':\t show who votes for whom today, if voting is open (alias 'tally')\n",
+		"'report [game id|last] [text|markdown|json] [file]':\t show or export the report of a finished game (alias 'rep')\n",
+		"'ready', 'unready':\t tell the host whether you are ready for the next game (alias 'r')\n",
+		"'start':\t start the game when everyone is ready, host only\n",
+		"'kick [player]':\t remove a player from the lobby, host only\n",
+		"'votekick [player]':\t vote to remove a player from the lobby or, during the day, from the game (alias 'vk')\n",
+		"'prefer [role|any] [role to avoid]':\t ask for the roles you'd like in the next games, only the odds change, without arguments the wishes are dropped (alias 'pref')\n",
+		"'mute [player]', 'unmute [player]':\t forbid or allow the player to chat, moderators only\n",
+		"'wait <seconds>':\t pause before the next command, useful in scripts (alias 'w')",
 	)
 }
 
@@ -36,12 +127,26 @@ func (c command) toString() string {
 	switch c {
 	case CONNECT:
 		return "connect"
+	case CREATE_ROOM:
+		return "create"
+	case ROOMS:
+		return "rooms"
 	case DISCONNECT:
 		return "disconnect"
 	case SHOW_PLAYER_LIST:
 		return "players"
 	case VOTE:
 		return "vote"
+	case ABSTAIN:
+		return "abstain"
+	case NOMINATE:
+		return "nominate"
+	case GUILTY:
+		return "guilty"
+	case INNOCENT:
+		return "innocent"
+	case CHECK:
+		return "check"
 	case EXPOSE:
 		return "expose"
 	case END_DAY:
@@ -50,6 +155,38 @@ func (c command) toString() string {
 		return "exit"
 	case CHAT:
 		return "chat"
+	case DIRECT_MSG:
+		return "dm"
+	case CHAT_HISTORY:
+		return "history"
+	case TEAM:
+		return "team"
+	case CHECKS:
+		return "checks"
+	case GAME_STATE:
+		return "state"
+	case VOTE_TALLY:
+		return "votes"
+	case REPORT:
+		return "report"
+	case READY:
+		return "ready"
+	case UNREADY:
+		return "unready"
+	case START:
+		return "start"
+	case KICK:
+		return "kick"
+	case VOTE_KICK:
+		return "votekick"
+	case PREFER:
+		return "prefer"
+	case MUTE:
+		return "mute"
+	case UNMUTE:
+		return "unmute"
+	case WAIT:
+		return "wait"
 	case HELP:
 		return "help"
 	default:
@@ -57,27 +194,182 @@ func (c command) toString() string {
 	}
 }
 
-func parseCommand(cmd string) command {
-	switch strings.ToLower(cmd) {
-	case CONNECT.toString():
-		return CONNECT
-	case DISCONNECT.toString():
-		return DISCONNECT
-	case SHOW_PLAYER_LIST.toString():
-		return SHOW_PLAYER_LIST
-	case VOTE.toString():
-		return VOTE
-	case EXPOSE.toString():
-		return EXPOSE
-	case END_DAY.toString():
-		return END_DAY
-	case EXIT.toString():
-		return EXIT
-	case CHAT.toString():
-		return CHAT
-	case HELP.toString():
-		return HELP
+func formatChatEntry(entry *proto.ChatEntry) string {
+	sentAt := time.Unix(entry.Timestamp, 0).Format("15:04:05")
+	switch entry.Channel {
+	case PUBLIC_CHANNEL:
+		return fmt.Sprintf("%s %s: %s", sentAt, entry.Sender, entry.Msg)
+	case DIRECT_CHANNEL:
+		return fmt.Sprintf("%s [%s] %s -> %s: %s", sentAt, entry.Channel, entry.Sender, entry.Recipient, entry.Msg)
 	default:
-		return UNKNOWN
+		return fmt.Sprintf("%s [%s] %s: %s", sentAt, entry.Channel, entry.Sender, entry.Msg)
+	}
+}
+
+// parseCommand splits the input line into a command and the rest of the line as its arguments
+func parseCommand(line string) (command, string) {
+	name, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	name = strings.ToLower(name)
+	args = strings.TrimSpace(args)
+
+	for _, cmd := range commands {
+		if cmd.toString() == name {
+			return cmd, args
+		}
+	}
+
+	if cmd, ok := aliases[name]; ok {
+		return cmd, args
+	}
+
+	return UNKNOWN, args
+}
+
+func formatGameState(state *proto.GameState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Game %s", state.Status)
+	if state.Phase != "" {
+		fmt.Fprintf(&b, ", %s %d", state.Phase, state.Round)
+	}
+	if state.Stage != "" {
+		fmt.Fprintf(&b, ", %s", state.Stage)
+	}
+	if state.Accused != "" {
+		fmt.Fprintf(&b, " of %s", state.Accused)
+	}
+	if state.SecondsLeft > 0 {
+		fmt.Fprintf(&b, ", %d seconds left", state.SecondsLeft)
+	}
+	if state.Room != "" {
+		fmt.Fprintf(&b, "\nRoom: %s", state.Room)
+	}
+	if state.Host != "" && state.Phase == "" {
+		fmt.Fprintf(&b, "\nHost: %s", state.Host)
+	}
+	if state.Role != "" {
+		fmt.Fprintf(&b, "\nYour role: %s", state.Role)
+	}
+	if state.Prefer != "" || state.Avoid != "" {
+		fmt.Fprintf(&b, "\nRole wishes: %s", formatPreference(state.Prefer, state.Avoid))
+	}
+	if state.Voted {
+		b.WriteString("\nYou have voted")
+	}
+	if state.Skipped {
+		b.WriteString("\nYou have skipped the day")
+	}
+
+	b.WriteString("\nPlayers:")
+	for _, player := range state.Players {
+		if player.Alive && player.Ready {
+			fmt.Fprintf(&b, "\n  %s (ready)", player.Name)
+		} else if player.Alive {
+			fmt.Fprintf(&b, "\n  %s", player.Name)
+		} else {
+			fmt.Fprintf(&b, "\n  %s (dead, was a %s)", player.Name, player.Role)
+		}
+	}
+
+	if len(state.Tally) > 0 || len(state.Abstainers) > 0 {
+		b.WriteString("\nVotes:\n")
+		b.WriteString(formatTally(state.Tally, state.Abstainers))
+	}
+
+	return b.String()
+}
+
+func formatTally(tally []*proto.VoteCount, abstainers []string) string {
+	lines := make([]string, 0, len(tally)+1)
+	for _, count := range tally {
+		line := fmt.Sprintf("  %s: %d", count.Target, count.Votes)
+		if len(count.Voters) > 0 {
+			line += " (" + strings.Join(count.Voters, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	if len(abstainers) > 0 {
+		lines = append(lines, fmt.Sprintf("  abstained: %d (%s)", len(abstainers), strings.Join(abstainers, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatRooms renders the public rooms of the server, the main one has no invite code
+func formatRooms(rooms []*proto.RoomInfo) string {
+	if len(rooms) == 0 {
+		return "There are no public rooms"
+	}
+
+	var b strings.Builder
+	b.WriteString("Rooms:")
+	for _, room := range rooms {
+		code := room.Code
+		if code == "" {
+			code = "main"
+		}
+		fmt.Fprintf(&b, "\n  %s: %d", code, room.Players)
+		if room.MaxPlayers > 0 {
+			fmt.Fprintf(&b, "/%d", room.MaxPlayers)
+		}
+		fmt.Fprintf(&b, " players, %s, %s rules", room.Status, room.Ruleset)
+		if room.Locked {
+			b.WriteString(", password")
+		}
+	}
+
+	return b.String()
+}
+
+// formatPreference describes the role wishes, e.g. "prefer detective, avoid mafia"
+func formatPreference(prefer, avoid string) string {
+	var parts []string
+	if prefer != "" {
+		parts = append(parts, "prefer "+prefer)
+	}
+	if avoid != "" {
+		parts = append(parts, "avoid "+avoid)
+	}
+	if len(parts) == 0 {
+		return "any role"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatTeam lists the allies with their roles, the dead ones are marked
+func formatTeam(team *proto.Team) string {
+	if len(team.Players) == 0 {
+		return "You don't know of any allies"
+	}
+
+	var b strings.Builder
+	b.WriteString("Your team:")
+	for _, player := range team.Players {
+		fmt.Fprintf(&b, "\n  %s (%s)", player.Name, player.Role)
+		if !player.Alive {
+			b.WriteString(", dead")
+		}
+	}
+	return b.String()
+}
+
+// formatInvestigations lists the night checks, for the Don a success means the detective has been found
+func formatInvestigations(checks *proto.Investigations) string {
+	if len(checks.Checks) == 0 {
+		return "No checks have been made yet"
+	}
+
+	var b strings.Builder
+	b.WriteString("Checks:")
+	for _, check := range checks.Checks {
+		result := "not a member of Mafia"
+		if check.Role == "don" && check.Found {
+			result = "the Detective"
+		} else if check.Role == "don" {
+			result = "not the Detective"
+		} else if check.Found {
+			result = "a member of Mafia"
+		}
+		fmt.Fprintf(&b, "\n  night %d, %s (%s): %s is %s", check.Round, check.Checker, check.Role, check.Target, result)
 	}
+	return b.String()
 }
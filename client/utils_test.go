@@ -0,0 +1,28 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	for _, test := range []struct {
+		line string
+		cmd  command
+		args string
+	}{
+		{"vote bob", VOTE, "bob"},
+		{"  VOTE   bob  ", VOTE, "bob"},
+		{"v bob", VOTE, "bob"},
+		{"chat #mafia let's get bob", CHAT, "#mafia let's get bob"},
+		{"pm alice hi there", DIRECT_MSG, "alice hi there"},
+		{"connect :8080 bob ABC123 secret", CONNECT, ":8080 bob ABC123 secret"},
+		{"skip", END_DAY, ""},
+		{"s", END_DAY, ""},
+		{"dance now", UNKNOWN, "now"},
+		{"", UNKNOWN, ""},
+	} {
+		if cmd, args := parseCommand(test.line); cmd != test.cmd || args != test.args {
+			t.Errorf("%q is parsed as %s %q instead of %s %q", test.line, cmd.toString(), args, test.cmd.toString(), test.args)
+		}
+	}
+}
@@ -5,19 +5,80 @@ import (
 	"log"
 	"mafia-core/client"
 	"mafia-core/server"
+	"strings"
 )
 
 var (
-	mode = flag.String("mode", "server", "Server or Client mode")
-	port = flag.Int("port", 8080, "Server port")
+	mode    = flag.String("mode", "server", "Server, Client or TUI mode")
+	port    = flag.Int("port", 8080, "Server port")
+	webPort = flag.Int("web-port", 8081, "Web client port")
+	address = flag.String("server", ":8080", "Server address for the TUI client")
+	name    = flag.String("name", "", "Nickname for the TUI client")
+	room    = flag.String("room", "", "Invite code of the room for the TUI client to join, the main room if empty")
+	passwd  = flag.String("password", "", "Password of the room for the TUI client")
+	exec    = flag.String("exec", "", "Commands for the client to execute non-interactively, separated by ';'")
+	script  = flag.String("script", "", "File with commands for the client to execute non-interactively, one per line")
+	filter  = flag.String("chat-filter", "", "File with words to mask in chat, one per line")
+	modTok  = flag.String("moderator-token", "", "Secret of the moderators: the server lets the clients connecting with it mute others, the clients send it")
+	policy  = flag.String("overflow-policy", "drop-oldest", "What to do when a player doesn't keep up with notifications: drop-oldest, disconnect or coalesce")
+	tie     = flag.String("tie-rule", server.DefaultRuleset.TieRule, "What to do when the day vote is tied: none, revote, random or all")
+	mafia   = flag.String("mafia-resolution", server.DefaultRuleset.MafiaResolution, "How the mafia picks the night victim: unanimous, majority or don")
+	don     = flag.Bool("don", server.DefaultRuleset.Don, "Make one of the mafia the Don, who may check a player for the detective at night")
+	maniac  = flag.Bool("maniac", server.DefaultRuleset.Maniac, "Add a neutral Maniac who kills alone at night and wins as the last one standing")
+	balance = flag.String("balance", server.DefaultRuleset.Balance, "How the roles are chosen for the number of players: table (tested compositions) or power (computed from role power values)")
+	reveal  = flag.String("death-reveal", server.DefaultRuleset.DeathReveal, "How much of a dead player's role is announced: full, team or none")
+	lovers  = flag.Bool("lovers", server.DefaultRuleset.Lovers, "Secretly link two random players who die together and win as the last two alive")
+	jester  = flag.Bool("jester", server.DefaultRuleset.Jester, "Add a neutral Jester who wins by being executed during the day")
+	night   = flag.Duration("night-time", server.DefaultRuleset.NightTime, "How long the mafia may discuss the victim at night")
+	earlyN  = flag.Bool("early-night-end", server.DefaultRuleset.EarlyNightEnd, "End the night before the night time runs out once the mafia is unanimous and the detectives, the Don and the Maniac have acted")
+	day     = flag.String("day-procedure", server.DefaultRuleset.DayProcedure, "How the day ends: plurality (the most voted player is executed) or trial (nominations, last words and a verdict)")
+	defence = flag.Duration("defence-time", server.DefaultRuleset.DefenceTime, "Time for the last words of the accused on a trial day")
+	verdict = flag.Duration("verdict-time", server.DefaultRuleset.VerdictTime, "Time to vote on the verdict on a trial day")
+	idle    = flag.Duration("idle-timeout", server.DefaultRuleset.IdleTimeout, "How long a player may do nothing during the day before the day is skipped for them, 0 turns the idle detector off")
+	idleLim = flag.Int("idle-limit", server.DefaultRuleset.IdleLimit, "How many phases in a row a player may miss before being turned into a ghost, 0 means never")
+	revealR = flag.Bool("reveal-removed", server.DefaultRuleset.RevealRemoved, "Announce the role of a player removed for idling or by a kick vote")
+	kickThr = flag.Float64("kick-threshold", server.DefaultRuleset.KickThreshold, "Share of kick votes the target has to exceed to be kicked")
+	teams   = flag.String("known-teams", strings.Join(server.DefaultRuleset.KnownTeams, ","), "Comma-separated teams whose members learn each other when the roles are dealt: mafia, detective, civilian, empty for none")
+	expose  = flag.String("expose-limit", server.DefaultRuleset.ExposeLimit, "How often a detective may reveal a finding to everyone: never, once (a game), daily or any")
+	share   = flag.Bool("share-checks", server.DefaultRuleset.ShareChecks, "Let the detectives see and reveal each other's checks")
+	open    = flag.Bool("open-voting", server.DefaultRuleset.OpenVoting, "Announce every day vote and verdict and show who voted for whom, otherwise the votes are secret")
+	dms     = flag.Bool("direct-messages", server.DefaultRuleset.DirectMessages, "Let the living players message each other privately during the day")
+	guilty  = flag.Float64("guilty-threshold", server.DefaultRuleset.GuiltyThreshold, "Share of guilty votes the accused has to exceed to be executed")
 )
 
 func main() {
 	flag.Parse()
 	log.Printf("Starting %s", *mode)
-	if *mode == "server" {
-		server.Run(*port)
-	} else {
-		client.Run()
+	switch *mode {
+	case "server":
+		overflowPolicy, err := server.ParseOverflowPolicy(*policy)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		rules := server.DefaultRuleset
+		rules.TieRule, rules.DeathReveal, rules.Balance = *tie, *reveal, *balance
+		rules.Don, rules.MafiaResolution, rules.NightTime, rules.EarlyNightEnd = *don, *mafia, *night, *earlyN
+		rules.Maniac, rules.Jester, rules.Lovers = *maniac, *jester, *lovers
+		rules.DayProcedure, rules.DefenceTime, rules.VerdictTime, rules.GuiltyThreshold = *day, *defence, *verdict, *guilty
+		rules.IdleTimeout, rules.IdleLimit, rules.RevealRemoved, rules.KickThreshold = *idle, *idleLim, *revealR, *kickThr
+		rules.ExposeLimit, rules.ShareChecks, rules.DirectMessages, rules.OpenVoting = *expose, *share, *dms, *open
+		rules.KnownTeams = strings.FieldsFunc(*teams, func(r rune) bool { return r == ',' })
+		if err := rules.Validate(); err != nil {
+			log.Fatalln(err)
+		}
+		server.Run(server.Options{
+			Port:           *port,
+			WebPort:        *webPort,
+			ChatFilterFile: *filter,
+			ModeratorToken: *modTok,
+			OverflowPolicy: overflowPolicy,
+			Rules:          rules,
+		})
+	case "tui":
+		client.SetModeratorToken(*modTok)
+		client.RunTUI(*address, *name, *room, *passwd)
+	default:
+		client.SetModeratorToken(*modTok)
+		client.Run(*exec, *script)
 	}
 }
@@ -20,12 +20,32 @@ const _ = grpc.SupportPackageIsVersion7
 type MafiaClient interface {
 	Connect(ctx context.Context, in *ClientInfo, opts ...grpc.CallOption) (*ClientId, error)
 	Disconnect(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
-	SubscribeToNotifications(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (Mafia_SubscribeToNotificationsClient, error)
-	ShowPlayersList(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*PlayersList, error)
+	SubscribeToNotifications(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (Mafia_SubscribeToNotificationsClient, error)
+	ShowPlayersList(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*PlayersList, error)
 	Vote(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
 	EndDay(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
-	Expose(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Expose(ctx context.Context, in *ExposeReq, opts ...grpc.CallOption) (*EmptyMsg, error)
 	Chat(ctx context.Context, in *ChatMsg, opts ...grpc.CallOption) (*EmptyMsg, error)
+	GetChatHistory(ctx context.Context, in *ChatHistoryReq, opts ...grpc.CallOption) (*ChatHistory, error)
+	Mute(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Unmute(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	GetGameState(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*GameState, error)
+	GetVoteTally(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*VoteTally, error)
+	Abstain(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Check(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Nominate(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Verdict(ctx context.Context, in *VerdictReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	GetGameReport(ctx context.Context, in *ReportReq, opts ...grpc.CallOption) (*Report, error)
+	Ready(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Unready(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
+	StartGame(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error)
+	Kick(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	VoteKick(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	CreateRoom(ctx context.Context, in *RoomReq, opts ...grpc.CallOption) (*RoomInfo, error)
+	ListRooms(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*RoomList, error)
+	SetRolePreference(ctx context.Context, in *RolePreferenceReq, opts ...grpc.CallOption) (*EmptyMsg, error)
+	GetTeam(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*Team, error)
+	GetInvestigations(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*Investigations, error)
 }
 
 type mafiaClient struct {
@@ -54,7 +74,7 @@ func (c *mafiaClient) Disconnect(ctx context.Context, in *ClientId, opts ...grpc
 	return out, nil
 }
 
-func (c *mafiaClient) SubscribeToNotifications(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (Mafia_SubscribeToNotificationsClient, error) {
+func (c *mafiaClient) SubscribeToNotifications(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (Mafia_SubscribeToNotificationsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Mafia_ServiceDesc.Streams[0], "/Mafia.Mafia/SubscribeToNotifications", opts...)
 	if err != nil {
 		return nil, err
@@ -86,7 +106,7 @@ func (x *mafiaSubscribeToNotificationsClient) Recv() (*Notification, error) {
 	return m, nil
 }
 
-func (c *mafiaClient) ShowPlayersList(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*PlayersList, error) {
+func (c *mafiaClient) ShowPlayersList(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*PlayersList, error) {
 	out := new(PlayersList)
 	err := c.cc.Invoke(ctx, "/Mafia.Mafia/ShowPlayersList", in, out, opts...)
 	if err != nil {
@@ -113,7 +133,7 @@ func (c *mafiaClient) EndDay(ctx context.Context, in *ClientId, opts ...grpc.Cal
 	return out, nil
 }
 
-func (c *mafiaClient) Expose(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error) {
+func (c *mafiaClient) Expose(ctx context.Context, in *ExposeReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
 	out := new(EmptyMsg)
 	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Expose", in, out, opts...)
 	if err != nil {
@@ -131,18 +151,218 @@ func (c *mafiaClient) Chat(ctx context.Context, in *ChatMsg, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *mafiaClient) GetChatHistory(ctx context.Context, in *ChatHistoryReq, opts ...grpc.CallOption) (*ChatHistory, error) {
+	out := new(ChatHistory)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetChatHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Mute(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Mute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Unmute(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Unmute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetGameState(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*GameState, error) {
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetGameState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetVoteTally(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*VoteTally, error) {
+	out := new(VoteTally)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetVoteTally", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Abstain(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Abstain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Check(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Check", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Nominate(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Nominate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Verdict(ctx context.Context, in *VerdictReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Verdict", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetGameReport(ctx context.Context, in *ReportReq, opts ...grpc.CallOption) (*Report, error) {
+	out := new(Report)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetGameReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Ready(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Ready", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Unready(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Unready", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) StartGame(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/StartGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) Kick(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/Kick", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) VoteKick(ctx context.Context, in *ClientReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/VoteKick", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) CreateRoom(ctx context.Context, in *RoomReq, opts ...grpc.CallOption) (*RoomInfo, error) {
+	out := new(RoomInfo)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/CreateRoom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) ListRooms(ctx context.Context, in *EmptyMsg, opts ...grpc.CallOption) (*RoomList, error) {
+	out := new(RoomList)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/ListRooms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) SetRolePreference(ctx context.Context, in *RolePreferenceReq, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/SetRolePreference", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetTeam(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*Team, error) {
+	out := new(Team)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetTeam", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mafiaClient) GetInvestigations(ctx context.Context, in *ClientId, opts ...grpc.CallOption) (*Investigations, error) {
+	out := new(Investigations)
+	err := c.cc.Invoke(ctx, "/Mafia.Mafia/GetInvestigations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MafiaServer is the server API for Mafia service.
 // All implementations must embed UnimplementedMafiaServer
 // for forward compatibility
 type MafiaServer interface {
 	Connect(context.Context, *ClientInfo) (*ClientId, error)
 	Disconnect(context.Context, *ClientId) (*EmptyMsg, error)
-	SubscribeToNotifications(*ClientId, Mafia_SubscribeToNotificationsServer) error
-	ShowPlayersList(context.Context, *EmptyMsg) (*PlayersList, error)
+	SubscribeToNotifications(*SubscribeReq, Mafia_SubscribeToNotificationsServer) error
+	ShowPlayersList(context.Context, *ClientId) (*PlayersList, error)
 	Vote(context.Context, *ClientReq) (*EmptyMsg, error)
 	EndDay(context.Context, *ClientId) (*EmptyMsg, error)
-	Expose(context.Context, *ClientId) (*EmptyMsg, error)
+	Expose(context.Context, *ExposeReq) (*EmptyMsg, error)
 	Chat(context.Context, *ChatMsg) (*EmptyMsg, error)
+	GetChatHistory(context.Context, *ChatHistoryReq) (*ChatHistory, error)
+	Mute(context.Context, *ClientReq) (*EmptyMsg, error)
+	Unmute(context.Context, *ClientReq) (*EmptyMsg, error)
+	GetGameState(context.Context, *ClientId) (*GameState, error)
+	GetVoteTally(context.Context, *ClientId) (*VoteTally, error)
+	Abstain(context.Context, *ClientId) (*EmptyMsg, error)
+	Check(context.Context, *ClientReq) (*EmptyMsg, error)
+	Nominate(context.Context, *ClientReq) (*EmptyMsg, error)
+	Verdict(context.Context, *VerdictReq) (*EmptyMsg, error)
+	GetGameReport(context.Context, *ReportReq) (*Report, error)
+	Ready(context.Context, *ClientId) (*EmptyMsg, error)
+	Unready(context.Context, *ClientId) (*EmptyMsg, error)
+	StartGame(context.Context, *ClientId) (*EmptyMsg, error)
+	Kick(context.Context, *ClientReq) (*EmptyMsg, error)
+	VoteKick(context.Context, *ClientReq) (*EmptyMsg, error)
+	CreateRoom(context.Context, *RoomReq) (*RoomInfo, error)
+	ListRooms(context.Context, *EmptyMsg) (*RoomList, error)
+	SetRolePreference(context.Context, *RolePreferenceReq) (*EmptyMsg, error)
+	GetTeam(context.Context, *ClientId) (*Team, error)
+	GetInvestigations(context.Context, *ClientId) (*Investigations, error)
 	mustEmbedUnimplementedMafiaServer()
 }
 
@@ -156,10 +376,10 @@ func (UnimplementedMafiaServer) Connect(context.Context, *ClientInfo) (*ClientId
 func (UnimplementedMafiaServer) Disconnect(context.Context, *ClientId) (*EmptyMsg, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
 }
-func (UnimplementedMafiaServer) SubscribeToNotifications(*ClientId, Mafia_SubscribeToNotificationsServer) error {
+func (UnimplementedMafiaServer) SubscribeToNotifications(*SubscribeReq, Mafia_SubscribeToNotificationsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeToNotifications not implemented")
 }
-func (UnimplementedMafiaServer) ShowPlayersList(context.Context, *EmptyMsg) (*PlayersList, error) {
+func (UnimplementedMafiaServer) ShowPlayersList(context.Context, *ClientId) (*PlayersList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ShowPlayersList not implemented")
 }
 func (UnimplementedMafiaServer) Vote(context.Context, *ClientReq) (*EmptyMsg, error) {
@@ -168,12 +388,72 @@ func (UnimplementedMafiaServer) Vote(context.Context, *ClientReq) (*EmptyMsg, er
 func (UnimplementedMafiaServer) EndDay(context.Context, *ClientId) (*EmptyMsg, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EndDay not implemented")
 }
-func (UnimplementedMafiaServer) Expose(context.Context, *ClientId) (*EmptyMsg, error) {
+func (UnimplementedMafiaServer) Expose(context.Context, *ExposeReq) (*EmptyMsg, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Expose not implemented")
 }
 func (UnimplementedMafiaServer) Chat(context.Context, *ChatMsg) (*EmptyMsg, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Chat not implemented")
 }
+func (UnimplementedMafiaServer) GetChatHistory(context.Context, *ChatHistoryReq) (*ChatHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChatHistory not implemented")
+}
+func (UnimplementedMafiaServer) Mute(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mute not implemented")
+}
+func (UnimplementedMafiaServer) Unmute(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unmute not implemented")
+}
+func (UnimplementedMafiaServer) GetGameState(context.Context, *ClientId) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameState not implemented")
+}
+func (UnimplementedMafiaServer) GetVoteTally(context.Context, *ClientId) (*VoteTally, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVoteTally not implemented")
+}
+func (UnimplementedMafiaServer) Abstain(context.Context, *ClientId) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Abstain not implemented")
+}
+func (UnimplementedMafiaServer) Check(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedMafiaServer) Nominate(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nominate not implemented")
+}
+func (UnimplementedMafiaServer) Verdict(context.Context, *VerdictReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verdict not implemented")
+}
+func (UnimplementedMafiaServer) GetGameReport(context.Context, *ReportReq) (*Report, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGameReport not implemented")
+}
+func (UnimplementedMafiaServer) Ready(context.Context, *ClientId) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ready not implemented")
+}
+func (UnimplementedMafiaServer) Unready(context.Context, *ClientId) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unready not implemented")
+}
+func (UnimplementedMafiaServer) StartGame(context.Context, *ClientId) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartGame not implemented")
+}
+func (UnimplementedMafiaServer) Kick(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kick not implemented")
+}
+func (UnimplementedMafiaServer) VoteKick(context.Context, *ClientReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteKick not implemented")
+}
+func (UnimplementedMafiaServer) CreateRoom(context.Context, *RoomReq) (*RoomInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoom not implemented")
+}
+func (UnimplementedMafiaServer) ListRooms(context.Context, *EmptyMsg) (*RoomList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRooms not implemented")
+}
+func (UnimplementedMafiaServer) SetRolePreference(context.Context, *RolePreferenceReq) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRolePreference not implemented")
+}
+func (UnimplementedMafiaServer) GetTeam(context.Context, *ClientId) (*Team, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeam not implemented")
+}
+func (UnimplementedMafiaServer) GetInvestigations(context.Context, *ClientId) (*Investigations, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvestigations not implemented")
+}
 func (UnimplementedMafiaServer) mustEmbedUnimplementedMafiaServer() {}
 
 // UnsafeMafiaServer may be embedded to opt out of forward compatibility for this service.
@@ -224,7 +504,7 @@ func _Mafia_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(in
 }
 
 func _Mafia_SubscribeToNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ClientId)
+	m := new(SubscribeReq)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -245,7 +525,7 @@ func (x *mafiaSubscribeToNotificationsServer) Send(m *Notification) error {
 }
 
 func _Mafia_ShowPlayersList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyMsg)
+	in := new(ClientId)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -257,7 +537,7 @@ func _Mafia_ShowPlayersList_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: "/Mafia.Mafia/ShowPlayersList",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MafiaServer).ShowPlayersList(ctx, req.(*EmptyMsg))
+		return srv.(MafiaServer).ShowPlayersList(ctx, req.(*ClientId))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -299,7 +579,7 @@ func _Mafia_EndDay_Handler(srv interface{}, ctx context.Context, dec func(interf
 }
 
 func _Mafia_Expose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ClientId)
+	in := new(ExposeReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -311,7 +591,7 @@ func _Mafia_Expose_Handler(srv interface{}, ctx context.Context, dec func(interf
 		FullMethod: "/Mafia.Mafia/Expose",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MafiaServer).Expose(ctx, req.(*ClientId))
+		return srv.(MafiaServer).Expose(ctx, req.(*ExposeReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -334,6 +614,366 @@ func _Mafia_Chat_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mafia_GetChatHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChatHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetChatHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetChatHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetChatHistory(ctx, req.(*ChatHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Mute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Mute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Mute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Mute(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Unmute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Unmute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Unmute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Unmute(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetGameState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetGameState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetGameState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetGameState(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetVoteTally_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetVoteTally(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetVoteTally",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetVoteTally(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Abstain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Abstain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Abstain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Abstain(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Check",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Check(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Nominate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Nominate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Nominate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Nominate(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Verdict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerdictReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Verdict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Verdict",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Verdict(ctx, req.(*VerdictReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetGameReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetGameReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetGameReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetGameReport(ctx, req.(*ReportReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Ready_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Ready(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Ready",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Ready(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Unready_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Unready(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Unready",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Unready(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_StartGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).StartGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/StartGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).StartGame(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/Kick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).Kick(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_VoteKick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).VoteKick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/VoteKick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).VoteKick(ctx, req.(*ClientReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/CreateRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).CreateRoom(ctx, req.(*RoomReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/ListRooms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).ListRooms(ctx, req.(*EmptyMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_SetRolePreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolePreferenceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).SetRolePreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/SetRolePreference",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).SetRolePreference(ctx, req.(*RolePreferenceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetTeam",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetTeam(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mafia_GetInvestigations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MafiaServer).GetInvestigations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Mafia.Mafia/GetInvestigations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MafiaServer).GetInvestigations(ctx, req.(*ClientId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Mafia_ServiceDesc is the grpc.ServiceDesc for Mafia service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -369,6 +1009,86 @@ var Mafia_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Chat",
 			Handler:    _Mafia_Chat_Handler,
 		},
+		{
+			MethodName: "GetChatHistory",
+			Handler:    _Mafia_GetChatHistory_Handler,
+		},
+		{
+			MethodName: "Mute",
+			Handler:    _Mafia_Mute_Handler,
+		},
+		{
+			MethodName: "Unmute",
+			Handler:    _Mafia_Unmute_Handler,
+		},
+		{
+			MethodName: "GetGameState",
+			Handler:    _Mafia_GetGameState_Handler,
+		},
+		{
+			MethodName: "GetVoteTally",
+			Handler:    _Mafia_GetVoteTally_Handler,
+		},
+		{
+			MethodName: "Abstain",
+			Handler:    _Mafia_Abstain_Handler,
+		},
+		{
+			MethodName: "Check",
+			Handler:    _Mafia_Check_Handler,
+		},
+		{
+			MethodName: "Nominate",
+			Handler:    _Mafia_Nominate_Handler,
+		},
+		{
+			MethodName: "Verdict",
+			Handler:    _Mafia_Verdict_Handler,
+		},
+		{
+			MethodName: "GetGameReport",
+			Handler:    _Mafia_GetGameReport_Handler,
+		},
+		{
+			MethodName: "Ready",
+			Handler:    _Mafia_Ready_Handler,
+		},
+		{
+			MethodName: "Unready",
+			Handler:    _Mafia_Unready_Handler,
+		},
+		{
+			MethodName: "StartGame",
+			Handler:    _Mafia_StartGame_Handler,
+		},
+		{
+			MethodName: "Kick",
+			Handler:    _Mafia_Kick_Handler,
+		},
+		{
+			MethodName: "VoteKick",
+			Handler:    _Mafia_VoteKick_Handler,
+		},
+		{
+			MethodName: "CreateRoom",
+			Handler:    _Mafia_CreateRoom_Handler,
+		},
+		{
+			MethodName: "ListRooms",
+			Handler:    _Mafia_ListRooms_Handler,
+		},
+		{
+			MethodName: "SetRolePreference",
+			Handler:    _Mafia_SetRolePreference_Handler,
+		},
+		{
+			MethodName: "GetTeam",
+			Handler:    _Mafia_GetTeam_Handler,
+		},
+		{
+			MethodName: "GetInvestigations",
+			Handler:    _Mafia_GetInvestigations_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+)
+
+// touch records that the player is still around, any command counts
+func (ms *mafiaSession) touch(id uint64) {
+	if player, ok := ms.players[id]; ok {
+		player.Touch()
+	}
+}
+
+// idleFor tells how long the player hasn't done anything since the phase started
+func idleFor(player MafiaPlayer, phaseStart time.Time) time.Duration {
+	if last := player.LastAction(); last.After(phaseStart) {
+		return time.Since(last)
+	}
+
+	return time.Since(phaseStart)
+}
+
+// watchIdlePlayers warns the living players who haven't skipped the day yet and skips it for them
+// once they have been idle for too long, so that a single absent player can't hold the whole game
+func (ms *mafiaSession) watchIdlePlayers(stop <-chan struct{}) {
+	if ms.rules.IdleTimeout == 0 {
+		return
+	}
+
+	phaseStart := time.Now()
+	warned := make(map[uint64]bool)
+	ticker := time.NewTicker(IDLE_CHECK_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		for id, player := range ms.players {
+			if player.GetRole() == GHOST || !player.IsActive() {
+				continue
+			}
+			idle := idleFor(player, phaseStart)
+			if idle >= ms.rules.IdleTimeout {
+				player.EndDay()
+				player.SetActive(false)
+				ms.NotifyPlayers(Notification{AFK_SKIPPED, player.GetName()}, ALL)
+				ms.missPhase(player)
+			} else if idle >= ms.rules.IdleTimeout/2 && !warned[id] {
+				warned[id] = true
+				left := (ms.rules.IdleTimeout - idle).Round(time.Second)
+				player.Notify(Notification{AFK_WARNING, strconv.Itoa(int(left.Seconds()))})
+			}
+		}
+	}
+}
+
+// checkNightIdlers counts a missed night for the players with a night action who haven't done anything,
+// the others aren't told who they are
+func (ms *mafiaSession) checkNightIdlers(nightStart time.Time) {
+	if ms.rules.IdleTimeout == 0 {
+		return
+	}
+
+	for _, player := range ms.players {
+		role := player.GetRole()
+		if (isMafia(role) || role == DETECTIVE || role == MANIAC) && !player.LastAction().After(nightStart) {
+			ms.missPhase(player)
+		}
+	}
+}
+
+// missPhase counts a phase the player has slept through and warns them before they are removed
+func (ms *mafiaSession) missPhase(player MafiaPlayer) {
+	missed := player.MissPhase()
+	player.Notify(Notification{AFK_MISSED, fmt.Sprintf("%d@@%d", missed, ms.rules.IdleLimit)})
+}
+
+// removeIdlePlayers turns the players who have missed too many phases in a row into ghosts
+func (ms *mafiaSession) removeIdlePlayers() []Notification {
+	var notifications []Notification
+	if ms.rules.IdleLimit == 0 {
+		return notifications
+	}
+
+	for id, player := range ms.players {
+		if player.GetRole() != GHOST && player.MissedPhases() >= ms.rules.IdleLimit {
+			log.Printf("Player %s has been removed for idling", player.GetName())
+			notifications = append(notifications, ms.removeFromGame(id, DEATH_AFK)...)
+		}
+	}
+
+	return notifications
+}
+
+// removeFromGame turns a living player into a ghost right away, without waiting for the end of the phase
+func (ms *mafiaSession) removeFromGame(id uint64, cause string) []Notification {
+	notifications := ms.eliminate(id, cause)
+	ms.withdrawPlayer(id)
+	return notifications
+}
+
+// withdrawPlayer takes a player who has just become a ghost out of the running phase: the votes and nominations
+// by and against the player are withdrawn and the phase ends at once if the game is over
+func (ms *mafiaSession) withdrawPlayer(id uint64) {
+	player := ms.players[id]
+	name := player.GetName()
+	if ms.phase == DAY && player.IsActive() {
+		// waitForDayEnd still waits for this player
+		player.EndDay()
+		player.SetActive(false)
+	}
+
+	ms.lock.Lock()
+	delete(ms.dayVotes, id)
+	delete(ms.abstentions, id)
+	delete(ms.verdicts, id)
+	for voter, target := range ms.dayVotes {
+		if target == name {
+			delete(ms.dayVotes, voter)
+		}
+	}
+	ms.nominees = withoutName(ms.nominees, name)
+	ms.revoteCandidates = withoutName(ms.revoteCandidates, name)
+	if ms.dayStage == VERDICT_STAGE && (ms.accused == name || len(ms.verdicts) >= ms.jurySize()) {
+		// there is nobody left to judge or to wait for
+		select {
+		case ms.verdictDone <- struct{}{}:
+		default:
+		}
+	}
+	ms.lock.Unlock()
+
+	ms.endDecidedPhase()
+}
+
+// endDecidedPhase stops waiting for the rest of the day or the night once the game is over, Start then ends the game
+func (ms *mafiaSession) endDecidedPhase() {
+	if !ms.endGameConditionReached() {
+		return
+	}
+	if ms.phase == NIGHT {
+		ms.lock.Lock()
+		select {
+		case ms.nightDone <- struct{}{}:
+		default:
+		}
+		ms.lock.Unlock()
+		return
+	}
+	for _, player := range ms.players {
+		if player.GetRole() != GHOST && player.IsActive() {
+			player.EndDay()
+			player.SetActive(false)
+		}
+	}
+}
+
+// withoutName returns the names except the given one
+func withoutName(names []string, name string) []string {
+	var res []string
+	for _, other := range names {
+		if other != name {
+			res = append(res, other)
+		}
+	}
+	return res
+}
+
+// kickVoteOpen tells whether players may vote to kick someone: in the lobby or during the day
+func (ms *mafiaSession) kickVoteOpen() bool {
+	return ms.inLobby() || ms.status == COUNTDOWN || (ms.status == IN_PROGRESS && ms.phase == DAY)
+}
+
+// VoteKick counts the player's vote to kick the target, who is removed from the lobby,
+// or turned into a ghost during the game, once the share of votes exceeds the threshold;
+// the id of the target is returned along with true if they have been removed from the session
+func (ms *mafiaSession) VoteKick(id uint64, target string) (uint64, bool, error) {
+	ms.touch(id)
+	ms.lock.Lock()
+	voter, ok := ms.players[id]
+	targetId, err := ms.getPlayersIdByName(target)
+	inGame := ms.status == IN_PROGRESS
+	if !ok {
+		ms.lock.Unlock()
+		return 0, false, playerRemovedError
+	} else if !ms.kickVoteOpen() {
+		ms.lock.Unlock()
+		return 0, false, kickVoteClosedError
+	} else if err != nil {
+		ms.lock.Unlock()
+		return 0, false, playerNotFoundError
+	} else if targetId == id {
+		ms.lock.Unlock()
+		return 0, false, kickSelfError
+	} else if inGame && voter.GetRole() == GHOST {
+		ms.lock.Unlock()
+		return 0, false, ghostKickVoteError
+	} else if inGame && ms.players[targetId].GetRole() == GHOST {
+		ms.lock.Unlock()
+		return 0, false, kickGhostError
+	}
+
+	if ms.kickVotes[target] == nil {
+		ms.kickVotes[target] = make(map[uint64]bool)
+	}
+	ms.kickVotes[target][id] = true
+	votes := len(ms.kickVotes[target])
+	// everyone but the target may vote, only the living ones during the game
+	voters := 0
+	for _, player := range ms.players {
+		if player.GetName() != target && (!inGame || player.GetRole() != GHOST) {
+			voters++
+		}
+	}
+	needed := int(math.Floor(ms.rules.KickThreshold*float64(voters))) + 1
+	passed := votes >= needed
+	if passed {
+		delete(ms.kickVotes, target)
+	}
+	ms.lock.Unlock()
+
+	ms.NotifyPlayers(Notification{KICK_VOTE, fmt.Sprintf("%s@@%s@@%d@@%d", voter.GetName(), target, votes, needed)}, ALL)
+	if !passed {
+		return targetId, false, nil
+	}
+
+	log.Printf("Player %s has been kicked by a vote", target)
+	if !inGame {
+		ms.NotifyPlayers(Notification{PLAYER_KICKED, target + "@@vote"}, ALL)
+		return targetId, true, ms.RemovePlayer(targetId)
+	}
+	for _, notification := range ms.removeFromGame(targetId, DEATH_KICK) {
+		ms.NotifyPlayers(notification, ALL)
+	}
+	return targetId, false, nil
+}
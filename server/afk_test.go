@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newTestGame makes a session in the middle of a game where the players are named p0, p1, ... and have the given roles
+func newTestGame(rules Ruleset, phase int, roles ...string) *mafiaSession {
+	s := &server{roleRecords: newRoleRecords()}
+	ms := s.newSession(rules, RoomSettings{})
+	for i, role := range roles {
+		ms.AddPlayer(uint64(i), fmt.Sprintf("p%d", i))
+		ms.players[uint64(i)].SetRole(role)
+		ms.players[uint64(i)].SetActive(true)
+	}
+	ms.inProcess, ms.status, ms.phase, ms.roundCnt = true, IN_PROGRESS, phase, 1
+	return ms
+}
+
+func TestRemoveFromGameWithdrawsVotes(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, MAFIA, CIVILIAN, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.dayVotes = map[uint64]string{0: "p2", 1: "p2", 3: "p0", 2: "p1"}
+	ms.nominees = []string{"p2", "p0"}
+
+	ms.removeFromGame(2, DEATH_KICK)
+	if role := ms.players[2].GetRole(); role != GHOST {
+		t.Fatalf("the removed player is a %s instead of a ghost", role)
+	}
+	for voter, target := range ms.dayVotes {
+		if target == "p2" || voter == 2 {
+			t.Errorf("the vote of %d against %s has been kept", voter, target)
+		}
+	}
+	if len(ms.dayVotes) != 1 || len(ms.nominees) != 1 || ms.nominees[0] != "p0" {
+		t.Errorf("only the votes and nominations of the others should be left, got %v and %v", ms.dayVotes, ms.nominees)
+	}
+
+	// the tally has been counted before the removal, the ghost isn't executed again
+	ms.executeDayVictims([]string{"p2"})
+	if len(ms.deaths) != 1 {
+		t.Errorf("the removed player has died %d times", len(ms.deaths))
+	}
+}
+
+func TestRemoveFromGameEndsDecidedDay(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+
+	ms.removeFromGame(0, DEATH_AFK)
+	if !ms.endGameConditionReached() {
+		t.Fatalf("the game should be over without the mafia")
+	}
+	for id, player := range ms.players {
+		if player.IsActive() {
+			t.Errorf("player %d still has the rest of the decided day", id)
+		}
+		if _, ended := player.WaitEndDay(); id != 0 && !ended {
+			t.Errorf("the day of player %d hasn't been ended", id)
+		}
+	}
+}
+
+func TestSkippedPlayerLeavesDecidedDay(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.PlayerEndDay(0)
+	dayEnded := make(chan struct{})
+	go func() {
+		ms.waitForDayEnd()
+		close(dayEnded)
+	}()
+	// let waitForDayEnd count the players before one of them leaves
+	time.Sleep(10 * time.Millisecond)
+
+	if err := ms.RemovePlayer(0); err != nil {
+		t.Fatalf("the mafia couldn't leave: %v", err)
+	}
+	select {
+	case <-dayEnded:
+	case <-time.After(time.Second):
+		t.Fatalf("the day goes on after the game has been decided")
+	}
+	if ms.status != IN_PROGRESS {
+		t.Errorf("the game should be ended by Start only, the status is %s", ms.status)
+	}
+}
+
+func TestPlayerLeavesDecidedNight(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, NIGHT, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.nightDone = make(chan struct{}, 1)
+
+	if err := ms.RemovePlayer(0); err != nil {
+		t.Fatalf("the mafia couldn't leave: %v", err)
+	}
+	select {
+	case <-ms.nightDone:
+	default:
+		t.Errorf("the night goes on after the game has been decided")
+	}
+	if ms.status != IN_PROGRESS {
+		t.Errorf("the game should be ended by Start only, the status is %s", ms.status)
+	}
+}
+
+func TestLeavingPlayerVotesWithdrawn(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, MAFIA, CIVILIAN, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.dayVotes = map[uint64]string{0: "p2", 1: "p2", 3: "p0", 2: "p1"}
+	ms.nominees = []string{"p2", "p0"}
+
+	if err := ms.RemovePlayer(2); err != nil {
+		t.Fatalf("the player couldn't leave: %v", err)
+	}
+	if len(ms.dayVotes) != 1 || len(ms.nominees) != 1 || ms.nominees[0] != "p0" {
+		t.Errorf("the votes and nominations by and against the leaving player should be withdrawn, got %v and %v", ms.dayVotes, ms.nominees)
+	}
+	if !ms.players[0].IsActive() {
+		t.Errorf("the day has been ended although the game goes on")
+	}
+}
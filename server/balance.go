@@ -0,0 +1,187 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// ---- role balancing modes
+const (
+	// BALANCE_TABLE picks one of the tested compositions for the number of players, at random by weight
+	BALANCE_TABLE = "table"
+	// BALANCE_POWER computes the composition whose role power values add up closest to zero
+	BALANCE_POWER = "power"
+)
+
+// rolePower is how much a role helps the town, a balanced composition adds up to about zero
+var rolePower = map[string]int{
+	CIVILIAN:  1,
+	DETECTIVE: 4,
+	MAFIA:     -4,
+	DON:       -5,
+	MANIAC:    -3,
+	JESTER:    -1,
+}
+
+// optionalRoleMinPlayers is the smallest game an enabled optional role is dealt in
+var optionalRoleMinPlayers = map[string]int{
+	DON:    PLAYERS_LOWER_LIM,
+	MANIAC: 6,
+	JESTER: 5,
+}
+
+// roleOrder is the order roles are listed in when the composition is announced
+var roleOrder = []string{DON, MAFIA, MANIAC, JESTER, DETECTIVE, CIVILIAN}
+
+// Composition is how many players get each role
+type Composition map[string]int
+
+// mafiaTeam counts the Don along with the other mafia members
+func (c Composition) mafiaTeam() int {
+	return c[MAFIA] + c[DON]
+}
+
+func (c Composition) power() int {
+	power := 0
+	for role, count := range c {
+		power += rolePower[role] * count
+	}
+
+	return power
+}
+
+// encode lists the roles of the composition as role=count separated by @@, the empty ones are left out
+func (c Composition) encode() string {
+	var parts []string
+	for _, role := range roleOrder {
+		if c[role] > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", role, c[role]))
+		}
+	}
+
+	return strings.Join(parts, "@@")
+}
+
+// formatComposition renders the encoded composition, e.g. "1 don, 1 mafia, 1 detective, 4 civilians"
+func formatComposition(info string) string {
+	var parts []string
+	for _, part := range strings.Split(info, "@@") {
+		role, count, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(count); err == nil && n > 1 && role != MAFIA {
+			role += "s"
+		}
+		parts = append(parts, count+" "+role)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// weightedComposition is a row of the composition table, the neutral roles are added on top of it
+// and the Don takes one of the mafia seats
+type weightedComposition struct {
+	weight     int
+	mafiaTeam  int
+	detectives int
+}
+
+// compositionTable holds the tested compositions by the number of players,
+// bigger games are balanced by the role power values
+var compositionTable = map[int][]weightedComposition{
+	4:  {{1, 1, 1}},
+	5:  {{1, 1, 1}},
+	6:  {{3, 2, 1}, {1, 1, 1}},
+	7:  {{3, 2, 1}, {1, 1, 1}},
+	8:  {{3, 2, 1}, {1, 2, 2}},
+	9:  {{2, 2, 1}, {2, 3, 2}},
+	10: {{3, 3, 1}, {1, 2, 1}},
+	11: {{3, 3, 1}, {1, 3, 2}},
+	12: {{3, 3, 1}, {1, 4, 2}},
+}
+
+// optionalRoles are the enabled roles besides the mafia, detectives and civilians that fit the game
+func (r Ruleset) optionalRoles(playerCnt int) []string {
+	var roles []string
+	for _, role := range []struct {
+		name    string
+		enabled bool
+	}{{DON, r.hasDon()}, {MANIAC, r.Maniac}, {JESTER, r.Jester}} {
+		if role.enabled && playerCnt >= optionalRoleMinPlayers[role.name] {
+			roles = append(roles, role.name)
+		}
+	}
+
+	return roles
+}
+
+// build fills the composition in: the Don leads the mafia, neutral roles and civilians take the rest
+func build(playerCnt, mafiaTeam, detectives int, optional []string) Composition {
+	c := Composition{MAFIA: mafiaTeam, DETECTIVE: detectives}
+	for _, role := range optional {
+		if role == DON {
+			c[MAFIA]--
+		}
+		c[role]++
+	}
+	c[CIVILIAN] = playerCnt - c[MAFIA] - c[DETECTIVE] - c[DON] - c[MANIAC] - c[JESTER]
+
+	return c
+}
+
+// balancedComposition picks the roles for the game, the room's ruleset tells which roles are enabled and how to balance them
+func (r Ruleset) balancedComposition(playerCnt int) Composition {
+	optional := r.optionalRoles(playerCnt)
+	neutrals := 0
+	for _, role := range optional {
+		if role != DON {
+			neutrals++
+		}
+	}
+	// the neutral roles take their seats first, the table is looked up for the rest of the players
+	if rows, ok := compositionTable[playerCnt-neutrals]; ok && r.Balance == BALANCE_TABLE {
+		total := 0
+		for _, row := range rows {
+			total += row.weight
+		}
+		pick := rand.Intn(total)
+		for _, row := range rows {
+			if pick < row.weight {
+				return build(playerCnt, row.mafiaTeam, row.detectives, optional)
+			}
+			pick -= row.weight
+		}
+	}
+
+	return powerComposition(playerCnt, optional)
+}
+
+// powerComposition tries every sensible mafia and detective count and keeps the composition closest to zero power,
+// the one with less mafia and then less detectives wins a tie
+func powerComposition(playerCnt int, optional []string) Composition {
+	var best Composition
+	for mafiaTeam := 1; mafiaTeam == 1 || mafiaTeam <= playerCnt/3; mafiaTeam++ {
+		for detectives := 1; detectives == 1 || detectives <= playerCnt/6; detectives++ {
+			c := build(playerCnt, mafiaTeam, detectives, optional)
+			if c[CIVILIAN] < 1 || c[MAFIA] < 0 {
+				continue
+			}
+			if best == nil || abs(c.power()) < abs(best.power()) {
+				best = c
+			}
+		}
+	}
+
+	return best
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
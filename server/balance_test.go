@@ -0,0 +1,58 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionalRoles(t *testing.T) {
+	rules := DefaultRuleset
+	rules.Don, rules.Maniac, rules.Jester = true, true, true
+	for n, expected := range map[int][]string{4: {DON}, 5: {DON, JESTER}, 6: {DON, MANIAC, JESTER}} {
+		if roles := rules.optionalRoles(n); !reflect.DeepEqual(roles, expected) {
+			t.Errorf("%d players get %v instead of %v", n, roles, expected)
+		}
+	}
+	if roles := DefaultRuleset.optionalRoles(12); roles != nil {
+		t.Errorf("the disabled roles are dealt: %v", roles)
+	}
+}
+
+func TestPowerComposition(t *testing.T) {
+	for n, expected := range map[int]Composition{
+		7:  {MAFIA: 2, DETECTIVE: 1, CIVILIAN: 4},
+		13: {MAFIA: 3, DETECTIVE: 1, CIVILIAN: 9},
+	} {
+		if c := powerComposition(n, nil); !reflect.DeepEqual(c, expected) {
+			t.Errorf("%d players get %v (power %d) instead of %v", n, c, c.power(), expected)
+		}
+	}
+
+	c := powerComposition(8, []string{DON, MANIAC})
+	if c[DON] != 1 || c[MANIAC] != 1 || c.mafiaTeam() < 1 || c[CIVILIAN]+c[DETECTIVE]+c.mafiaTeam()+c[MANIAC] != 8 {
+		t.Errorf("the optional roles are missing from %v", c)
+	}
+}
+
+func TestTableComposition(t *testing.T) {
+	rules := DefaultRuleset
+	rules.Maniac, rules.Jester = true, true
+	for i := 0; i < 50; i++ {
+		// the neutral roles take two seats, the rest is looked up for six players
+		c := rules.balancedComposition(8)
+		row := weightedComposition{mafiaTeam: c.mafiaTeam(), detectives: c[DETECTIVE]}
+		if c[MANIAC] != 1 || c[JESTER] != 1 || (row != weightedComposition{mafiaTeam: 2, detectives: 1} && row != weightedComposition{mafiaTeam: 1, detectives: 1}) {
+			t.Fatalf("%v isn't one of the tested compositions", c)
+		}
+	}
+}
+
+func TestEncodeComposition(t *testing.T) {
+	info := Composition{DON: 1, MAFIA: 1, DETECTIVE: 1, CIVILIAN: 4, JESTER: 0}.encode()
+	if info != "don=1@@mafia=1@@detective=1@@civilian=4" {
+		t.Errorf("the composition is encoded as %q", info)
+	}
+	if text := formatComposition(info); text != "1 don, 1 mafia, 1 detective, 4 civilians" {
+		t.Errorf("the composition is rendered as %q", text)
+	}
+}
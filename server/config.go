@@ -1,11 +1,185 @@
 package server
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 const (
-	PLAYERS_LOWER_LIM  = 4
-	PLAYERS_MID_LIM    = 7
-	PLAYERS_UPPER_LIM  = 12
-	START_DELAY        = 10 * time.Second
+	PLAYERS_LOWER_LIM = 4
+	PLAYERS_MID_LIM   = 7
+	PLAYERS_UPPER_LIM = 12
+	START_DELAY       = 10 * time.Second
+	// players who haven't got ready for a rematch by then are removed from the lobby
+	REMATCH_TIMEOUT    = 60 * time.Second
 	NOTIFICATION_DELAY = 1 * time.Second
+	CHAT_MAX_MSG_LEN   = 300
+	// non-critical notifications above this limit are handled by the overflow policy
+	NOTIFICATION_QUEUE_SIZE = 10
+	// number of the latest notifications kept for every player to catch up late subscriptions
+	NOTIFICATION_HISTORY_SIZE = 1000
+	// chat rate limit: messages per second and the largest burst
+	CHAT_RATE  = 0.5
+	CHAT_BURST = 5
+	// how often the living players are checked for idling during the day
+	IDLE_CHECK_INTERVAL = 5 * time.Second
+	// the most players a created room may be limited to and the most rooms a server keeps at once
+	ROOM_SIZE_LIM = 20
+	ROOMS_LIM     = 100
+)
+
+// ---- rule presets a room can be created with
+const (
+	// RULESET_SERVER is the one the server has been launched with
+	RULESET_SERVER   = "server"
+	RULESET_CLASSIC  = "classic"
+	RULESET_TRIAL    = "trial"
+	RULESET_EXTENDED = "extended"
 )
+
+// Options are set from the command line when the server is launched
+type Options struct {
+	Port           int
+	WebPort        int
+	ChatFilterFile string
+	ModeratorToken string
+	OverflowPolicy OverflowPolicy
+	Rules          Ruleset
+}
+
+// Ruleset holds optional game rules of a session
+type Ruleset struct {
+	// DirectMessages allows living players to message each other privately during the day
+	DirectMessages bool
+	// OpenVoting announces every day vote and shows who voted for whom
+	OpenVoting bool
+	// TieRule tells what happens when several players get the most votes: TIE_NO_EXECUTION, TIE_REVOTE, TIE_RANDOM or TIE_EXECUTE_ALL
+	TieRule string
+	// Don makes one of the mafia members the Don, who may check a player for the detective at night
+	Don bool
+	// Maniac adds a neutral killer who acts alone at night and wins by being the last one standing
+	Maniac bool
+	// Jester adds a neutral player who wins by being executed during the day
+	Jester bool
+	// Lovers secretly links two random players who share a chat, die together and win as the last two alive
+	Lovers bool
+	// Balance is how the roles are chosen for the number of players: BALANCE_TABLE or BALANCE_POWER
+	Balance string
+	// DeathReveal is how much of the role is announced when a player dies, one of the REVEAL_* modes
+	DeathReveal string
+	// MafiaResolution is how the mafia chooses the night victim: MAFIA_UNANIMOUS, MAFIA_MAJORITY or MAFIA_DON
+	MafiaResolution string
+	// NightTime is how long the mafia may discuss and change proposals
+	NightTime time.Duration
+	// EarlyNightEnd ends the night before NightTime runs out once the mafia is unanimous and everyone else with a night action has acted
+	EarlyNightEnd bool
+	// DayProcedure is PLURALITY_DAY or TRIAL_DAY
+	DayProcedure string
+	// DefenceTime is how long the accused may speak before the verdict on a trial day
+	DefenceTime time.Duration
+	// VerdictTime is how long players may vote on the verdict
+	VerdictTime time.Duration
+	// GuiltyThreshold is the share of guilty votes among the cast ones the accused has to exceed to be executed
+	GuiltyThreshold float64
+	// IdleTimeout is how long a player may do nothing during the day before the day is skipped for them, zero turns the idle detector off
+	IdleTimeout time.Duration
+	// IdleLimit is how many phases in a row a player may miss before being turned into a ghost, zero means never
+	IdleLimit int
+	// RevealRemoved announces the role of a player removed for idling or by a kick vote the same way as on death, it stays secret otherwise
+	RevealRemoved bool
+	// KnownTeams are the teams whose members learn each other's names and roles when the roles are dealt: MAFIA, DETECTIVE or CIVILIAN,
+	// the Don belongs to the mafia
+	KnownTeams []string
+	// ExposeLimit is how often a detective may reveal a finding to everyone: EXPOSE_NEVER, EXPOSE_ONCE, EXPOSE_DAILY or EXPOSE_ANY
+	ExposeLimit string
+	// ShareChecks lets the detectives see each other's checks and reveal them
+	ShareChecks bool
+	// KickThreshold is the share of kick votes among the players who may vote the target has to exceed to be kicked
+	KickThreshold float64
+}
+
+var DefaultRuleset = Ruleset{
+	DirectMessages:  true,
+	OpenVoting:      true,
+	TieRule:         TIE_NO_EXECUTION,
+	Don:             false,
+	Maniac:          false,
+	Jester:          false,
+	Lovers:          false,
+	Balance:         BALANCE_TABLE,
+	DeathReveal:     REVEAL_FULL,
+	MafiaResolution: MAFIA_UNANIMOUS,
+	NightTime:       60 * time.Second,
+	EarlyNightEnd:   false,
+	DayProcedure:    PLURALITY_DAY,
+	DefenceTime:     30 * time.Second,
+	VerdictTime:     30 * time.Second,
+	GuiltyThreshold: 0.5,
+	IdleTimeout:     2 * time.Minute,
+	IdleLimit:       2,
+	RevealRemoved:   true,
+	KickThreshold:   0.5,
+	KnownTeams:      []string{MAFIA},
+	ExposeLimit:     EXPOSE_DAILY,
+	ShareChecks:     false,
+}
+
+// rulesetPresets are the rules a room may be created with, the server ones are set from the command line
+func rulesetPresets(server Ruleset) map[string]Ruleset {
+	classic := DefaultRuleset
+	classic.DirectMessages = false
+	trial := DefaultRuleset
+	trial.DayProcedure, trial.OpenVoting = TRIAL_DAY, false
+	extended := DefaultRuleset
+	extended.Don, extended.Maniac, extended.Jester, extended.Lovers = true, true, true, true
+
+	return map[string]Ruleset{
+		RULESET_SERVER:   server,
+		RULESET_CLASSIC:  classic,
+		RULESET_TRIAL:    trial,
+		RULESET_EXTENDED: extended,
+	}
+}
+
+// hasDon tells whether the mafia is led by the Don, which the MAFIA_DON resolution needs anyway
+func (r Ruleset) hasDon() bool {
+	return r.Don || r.MafiaResolution == MAFIA_DON
+}
+
+// Validate checks the values that can be set from the command line
+func (r Ruleset) Validate() error {
+	if r.DayProcedure != PLURALITY_DAY && r.DayProcedure != TRIAL_DAY {
+		return fmt.Errorf("unknown day procedure '%s', expected %s or %s", r.DayProcedure, PLURALITY_DAY, TRIAL_DAY)
+	}
+	if r.TieRule != TIE_NO_EXECUTION && r.TieRule != TIE_REVOTE && r.TieRule != TIE_RANDOM && r.TieRule != TIE_EXECUTE_ALL {
+		return fmt.Errorf("unknown tie rule '%s', expected %s, %s, %s or %s", r.TieRule, TIE_NO_EXECUTION, TIE_REVOTE, TIE_RANDOM, TIE_EXECUTE_ALL)
+	}
+	if r.MafiaResolution != MAFIA_UNANIMOUS && r.MafiaResolution != MAFIA_MAJORITY && r.MafiaResolution != MAFIA_DON {
+		return fmt.Errorf("unknown mafia resolution '%s', expected %s, %s or %s", r.MafiaResolution, MAFIA_UNANIMOUS, MAFIA_MAJORITY, MAFIA_DON)
+	}
+	if r.Balance != BALANCE_TABLE && r.Balance != BALANCE_POWER {
+		return fmt.Errorf("unknown balance mode '%s', expected %s or %s", r.Balance, BALANCE_TABLE, BALANCE_POWER)
+	}
+	if r.DeathReveal != REVEAL_FULL && r.DeathReveal != REVEAL_TEAM && r.DeathReveal != REVEAL_NONE {
+		return fmt.Errorf("unknown death reveal mode '%s', expected %s, %s or %s", r.DeathReveal, REVEAL_FULL, REVEAL_TEAM, REVEAL_NONE)
+	}
+	if r.ExposeLimit != EXPOSE_NEVER && r.ExposeLimit != EXPOSE_ONCE && r.ExposeLimit != EXPOSE_DAILY && r.ExposeLimit != EXPOSE_ANY {
+		return fmt.Errorf("unknown expose limit '%s', expected %s, %s, %s or %s", r.ExposeLimit, EXPOSE_NEVER, EXPOSE_ONCE, EXPOSE_DAILY, EXPOSE_ANY)
+	}
+	if r.GuiltyThreshold < 0 || r.GuiltyThreshold >= 1 {
+		return fmt.Errorf("guilty threshold must be in [0, 1), got %v", r.GuiltyThreshold)
+	}
+	if r.KickThreshold < 0 || r.KickThreshold >= 1 {
+		return fmt.Errorf("kick threshold must be in [0, 1), got %v", r.KickThreshold)
+	}
+	if r.IdleTimeout < 0 || r.IdleLimit < 0 {
+		return fmt.Errorf("idle timeout and idle limit can't be negative")
+	}
+	for _, team := range r.KnownTeams {
+		if team != MAFIA && team != DETECTIVE && team != CIVILIAN {
+			return fmt.Errorf("unknown team '%s', expected %s, %s or %s", team, MAFIA, DETECTIVE, CIVILIAN)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// ---- factions, named the way they are announced at the end of the game
+const (
+	TOWN_FACTION   = "civilians"
+	MAFIA_FACTION  = "mafia"
+	MANIAC_FACTION = "maniac"
+	JESTER_FACTION = "jester"
+	LOVERS_FACTION = "lovers"
+)
+
+// LOVER is shown in place of the partner's role among the allies, the lovers don't learn each other's roles
+const LOVER = "lover"
+
+// factionOf returns the faction a living player's role plays for
+func factionOf(role string) string {
+	switch role {
+	case MAFIA, DON:
+		return MAFIA_FACTION
+	case MANIAC:
+		return MANIAC_FACTION
+	case JESTER:
+		return JESTER_FACTION
+	}
+
+	return TOWN_FACTION
+}
+
+// winners checks the win condition of every faction against the living players and tells whether the game is over,
+// factions that have already won during the game (the executed jester) are among the winners as well
+func (ms *mafiaSession) winners() ([]string, bool) {
+	alive := make(map[string]int)
+	total := 0
+	for _, player := range ms.players {
+		if player.GetRole() == GHOST || player.GetRole() == "" {
+			continue
+		}
+		alive[factionOf(player.GetRole())]++
+		total++
+	}
+
+	winners := append([]string(nil), ms.extraWinners...)
+	if total == 2 && ms.loversAlive() {
+		// love wins over any team
+		return append(winners, LOVERS_FACTION), true
+	}
+	mafia, maniac := alive[MAFIA_FACTION], alive[MANIAC_FACTION]
+	if maniac > 0 && total-maniac <= 1 {
+		// nobody can stop the maniac at night anymore
+		return append(winners, MANIAC_FACTION), true
+	} else if mafia == 0 && maniac == 0 {
+		if total > 0 {
+			winners = append(winners, TOWN_FACTION)
+		}
+		return winners, true
+	} else if maniac == 0 && mafia >= total-mafia {
+		return append(winners, MAFIA_FACTION), true
+	}
+
+	return winners, false
+}
+
+// formatWinners renders the SESSION_END info
+func formatWinners(info string) string {
+	if info == "" {
+		return "nobody has won"
+	}
+
+	var names []string
+	for _, faction := range strings.Split(info, "@@") {
+		switch faction {
+		case TOWN_FACTION:
+			names = append(names, "civilians")
+		case MAFIA_FACTION, LOVERS_FACTION:
+			names = append(names, faction)
+		default:
+			names = append(names, "the "+faction)
+		}
+	}
+
+	verb := "has"
+	if len(names) > 1 || names[0] == "civilians" || names[0] == "lovers" {
+		verb = "have"
+	}
+	return strings.Join(names, " and ") + " " + verb + " won"
+}
+
+// linkLovers secretly links two random players, each of them learns only the partner's name
+func (ms *mafiaSession) linkLovers() {
+	if !ms.rules.Lovers {
+		return
+	}
+
+	ids := make([]uint64, 0, len(ms.players))
+	for id := range ms.players {
+		ids = append(ids, id)
+	}
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	ms.lovers = ids[:2]
+	ms.players[ms.lovers[0]].Notify(Notification{LOVERS_LINKED, ms.players[ms.lovers[1]].GetName()})
+	ms.players[ms.lovers[1]].Notify(Notification{LOVERS_LINKED, ms.players[ms.lovers[0]].GetName()})
+}
+
+func (ms *mafiaSession) isLover(id uint64) bool {
+	_, ok := ms.partnerOf(id)
+	return ok
+}
+
+// partnerOf returns the id of the player the given one is linked with
+func (ms *mafiaSession) partnerOf(id uint64) (uint64, bool) {
+	if len(ms.lovers) != 2 {
+		return 0, false
+	} else if ms.lovers[0] == id {
+		return ms.lovers[1], true
+	} else if ms.lovers[1] == id {
+		return ms.lovers[0], true
+	}
+
+	return 0, false
+}
+
+// loversAlive tells whether both lovers are still in the game
+func (ms *mafiaSession) loversAlive() bool {
+	if len(ms.lovers) != 2 {
+		return false
+	}
+	for _, id := range ms.lovers {
+		if player, ok := ms.players[id]; !ok || player.GetRole() == GHOST {
+			return false
+		}
+	}
+
+	return true
+}
+
+// teamOf returns the team whose members may know each other, the lone roles have none
+func teamOf(role string) string {
+	switch role {
+	case MAFIA, DON:
+		return MAFIA
+	case DETECTIVE, CIVILIAN:
+		return role
+	}
+
+	return ""
+}
+
+// dealtRole is the role the player has got at the start of the game, the dead ones are ghosts by now
+func (ms *mafiaSession) dealtRole(player MafiaPlayer) string {
+	if role, dead := ms.graveyard[player.GetName()]; dead {
+		return role
+	}
+	return player.GetRole()
+}
+
+// knowsTeam tells whether the ruleset lets the members of the role's team see each other
+func (r Ruleset) knowsTeam(role string) bool {
+	team := teamOf(role)
+	for _, known := range r.KnownTeams {
+		if team != "" && known == team {
+			return true
+		}
+	}
+	return false
+}
+
+// allies lists the teammates the player is allowed to know with their roles, and the lover, whose role stays secret;
+// the caller holds the lock
+func (ms *mafiaSession) allies(id uint64) []PlayerState {
+	role := ms.dealtRole(ms.players[id])
+	var res []PlayerState
+	for pId, player := range ms.players {
+		if pId == id || player.GetRole() == "" {
+			continue
+		}
+		_, dead := ms.graveyard[player.GetName()]
+		if other := ms.dealtRole(player); ms.rules.knowsTeam(role) && teamOf(other) == teamOf(role) {
+			res = append(res, PlayerState{Name: player.GetName(), Alive: !dead, Role: other})
+		} else if partnerId, ok := ms.partnerOf(id); ok && partnerId == pId {
+			res = append(res, PlayerState{Name: player.GetName(), Alive: !dead, Role: LOVER})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+// GetTeam returns the visible allies of the player in the current game
+func (ms *mafiaSession) GetTeam(id uint64) ([]PlayerState, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if _, ok := ms.players[id]; !ok {
+		return nil, playerRemovedError
+	}
+	if ms.status != IN_PROGRESS {
+		return nil, notInGameError
+	}
+	return ms.allies(id), nil
+}
+
+// encodeRoleAssignment makes the ROLE_ASSIGNED info: the role followed by the known teammates as "name role", separated by @@
+func (ms *mafiaSession) encodeRoleAssignment(id uint64) string {
+	parts := []string{ms.players[id].GetRole()}
+	for _, ally := range ms.allies(id) {
+		if ally.Role != LOVER {
+			parts = append(parts, ally.Name+" "+ally.Role)
+		}
+	}
+	return strings.Join(parts, "@@")
+}
+
+// formatRoleAssignment renders the ROLE_ASSIGNED info
+func formatRoleAssignment(info string) string {
+	parts := strings.Split(info, "@@")
+	res := "You have been assigned the role of: " + parts[0]
+	if len(parts) == 1 {
+		return res
+	}
+
+	var mates []string
+	for _, mate := range parts[1:] {
+		name, role, _ := strings.Cut(mate, " ")
+		mates = append(mates, fmt.Sprintf("%s (%s)", name, role))
+	}
+	return res + "\nYour team: " + strings.Join(mates, ", ")
+}
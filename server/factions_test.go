@@ -0,0 +1,107 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWinners(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		roles   []string
+		lovers  []uint64
+		extra   []string
+		winners []string
+		ended   bool
+	}{
+		{"game goes on", []string{MAFIA, CIVILIAN, CIVILIAN, DETECTIVE}, nil, nil, nil, false},
+		{"mafia caught", []string{GHOST, CIVILIAN, DETECTIVE}, nil, nil, []string{TOWN_FACTION}, true},
+		{"mafia at parity", []string{MAFIA, DON, CIVILIAN, DETECTIVE, GHOST}, nil, nil, []string{MAFIA_FACTION}, true},
+		{"maniac stops the mafia", []string{MAFIA, MAFIA, MANIAC, CIVILIAN}, nil, nil, nil, false},
+		{"maniac left with one", []string{MANIAC, MAFIA, GHOST}, nil, nil, []string{MANIAC_FACTION}, true},
+		{"jester doesn't stop the town", []string{GHOST, JESTER, CIVILIAN, CIVILIAN}, nil, nil, []string{TOWN_FACTION}, true},
+		{"executed jester wins too", []string{GHOST, GHOST, CIVILIAN}, nil, []string{JESTER_FACTION}, []string{JESTER_FACTION, TOWN_FACTION}, true},
+		{"lovers left alone", []string{MAFIA, CIVILIAN, GHOST, GHOST}, []uint64{0, 1}, nil, []string{LOVERS_FACTION}, true},
+		{"a lover has died", []string{MAFIA, CIVILIAN, GHOST}, []uint64{1, 2}, nil, []string{MAFIA_FACTION}, true},
+		{"everyone is dead", []string{GHOST, GHOST}, nil, nil, nil, true},
+	} {
+		ms := newTestGame(DefaultRuleset, NIGHT, test.roles...)
+		ms.lovers, ms.extraWinners = test.lovers, test.extra
+		winners, ended := ms.winners()
+		if ended != test.ended || !reflect.DeepEqual(winners, test.winners) {
+			t.Errorf("%s: got %v (over: %t), expected %v (over: %t)", test.name, winners, ended, test.winners, test.ended)
+		}
+	}
+}
+
+func TestFormatWinners(t *testing.T) {
+	for info, text := range map[string]string{
+		"":                                   "nobody has won",
+		MAFIA_FACTION:                        "mafia has won",
+		TOWN_FACTION:                         "civilians have won",
+		JESTER_FACTION + "@@" + TOWN_FACTION: "the jester and civilians have won",
+	} {
+		if got := formatWinners(info); got != text {
+			t.Errorf("%q is rendered as %q instead of %q", info, got, text)
+		}
+	}
+}
+
+func TestLovers(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.linkLovers()
+	if ms.lovers != nil {
+		t.Fatalf("the lovers have been linked without the rule: %v", ms.lovers)
+	}
+
+	ms.rules.Lovers = true
+	ms.linkLovers()
+	if len(ms.lovers) != 2 || ms.lovers[0] == ms.lovers[1] {
+		t.Fatalf("two different players should be linked, got %v", ms.lovers)
+	}
+	first, second := ms.lovers[0], ms.lovers[1]
+	if partner, ok := ms.partnerOf(first); !ok || partner != second {
+		t.Errorf("the partner of %d is %d instead of %d", first, partner, second)
+	}
+	team, _ := ms.GetTeam(first)
+	if len(team) != 1 || team[0].Name != ms.players[second].GetName() || team[0].Role != LOVER {
+		t.Errorf("the lover should be shown without the role among the allies, got %v", team)
+	}
+
+	notifications := ms.eliminate(first, DEATH_EXECUTION)
+	if len(notifications) != 2 || !strings.HasSuffix(notifications[1].info, " "+DEATH_HEARTBREAK) {
+		t.Errorf("the partner should die of a broken heart, got %v", notifications)
+	}
+	if ms.players[second].GetRole() != GHOST || len(ms.deaths) != 2 || ms.deaths[1].cause != DEATH_HEARTBREAK {
+		t.Errorf("the partner hasn't died with the lover: %v", ms.deaths)
+	}
+}
+
+func TestKnownTeams(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, NIGHT, DON, MAFIA, CIVILIAN, DETECTIVE, DETECTIVE)
+	for id, info := range map[uint64]string{0: "don@@p1 mafia", 1: "mafia@@p0 don", 2: CIVILIAN, 3: DETECTIVE} {
+		if assignment := ms.encodeRoleAssignment(id); assignment != info {
+			t.Errorf("p%d is assigned %q instead of %q", id, assignment, info)
+		}
+	}
+	if text := formatRoleAssignment("don@@p1 mafia"); text != "You have been assigned the role of: don\nYour team: p1 (mafia)" {
+		t.Errorf("the role assignment is rendered as %q", text)
+	}
+
+	ms.rules.KnownTeams = []string{MAFIA, DETECTIVE}
+	if assignment := ms.encodeRoleAssignment(3); assignment != "detective@@p4 detective" {
+		t.Errorf("the detectives should know each other, got %q", assignment)
+	}
+
+	// the dead teammates are still shown with their dealt role
+	ms.eliminate(1, DEATH_EXECUTION)
+	team, err := ms.GetTeam(0)
+	if err != nil || !reflect.DeepEqual(team, []PlayerState{{Name: "p1", Alive: false, Role: MAFIA}}) {
+		t.Errorf("the Don sees the team as %v, %v", team, err)
+	}
+	ms.status = ENDED
+	if _, err := ms.GetTeam(0); err != notInGameError {
+		t.Errorf("the team outside of a game should fail with %v, got %v", notInGameError, err)
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// ---- fair role assignment
+const (
+	// ROLE_HISTORY_SIZE is how many last roles of a player are remembered
+	ROLE_HISTORY_SIZE = 10
+	// ROLE_WEIGHT_LIM bounds how much the history and preferences may change the odds of a role either way,
+	// so that nobody's role can be predicted
+	ROLE_WEIGHT_LIM = 4.0
+	// STREAK_WEIGHT is added to the odds of a special role for every game in a row played as a civilian
+	STREAK_WEIGHT = 0.5
+	// REPEAT_WEIGHT scales the odds of getting the same role as in the last game
+	REPEAT_WEIGHT = 0.5
+	// PREFERENCE_WEIGHT scales the odds of a preferred role up and of an avoided one down
+	PREFERENCE_WEIGHT = 2.0
+	// ANY_ROLE means no preference
+	ANY_ROLE = ""
+)
+
+// RolePreference is what the player would like to play and what they would rather not,
+// the wishes are honored only as far as ROLE_WEIGHT_LIM allows
+type RolePreference struct {
+	Prefer string
+	Avoid  string
+}
+
+// roleRecords keeps the roles and preferences of the players by name, it is shared by all the rooms of the server
+type roleRecords struct {
+	history     map[string][]string
+	preferences map[string]RolePreference
+	lock        sync.Mutex
+}
+
+func newRoleRecords() *roleRecords {
+	return &roleRecords{
+		history:     make(map[string][]string),
+		preferences: make(map[string]RolePreference),
+	}
+}
+
+func (rr *roleRecords) setPreference(name string, pref RolePreference) error {
+	for _, role := range []string{pref.Prefer, pref.Avoid} {
+		if role != ANY_ROLE && rolePower[role] == 0 {
+			return unknownRoleError
+		}
+	}
+	if pref.Prefer != ANY_ROLE && pref.Prefer == pref.Avoid {
+		return preferenceConflictError
+	}
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	if pref == (RolePreference{}) {
+		delete(rr.preferences, name)
+	} else {
+		rr.preferences[name] = pref
+	}
+	return nil
+}
+
+func (rr *roleRecords) preference(name string) RolePreference {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	return rr.preferences[name]
+}
+
+// candidate gathers what the dealing needs to know about the player
+func (rr *roleRecords) candidate(name string) roleCandidate {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	return roleCandidate{
+		preference: rr.preferences[name],
+		history:    append([]string(nil), rr.history[name]...),
+	}
+}
+
+func (rr *roleRecords) record(name, role string) {
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+	history := append(rr.history[name], role)
+	if len(history) > ROLE_HISTORY_SIZE {
+		history = history[len(history)-ROLE_HISTORY_SIZE:]
+	}
+	rr.history[name] = history
+}
+
+// roleCandidate is a player waiting for a role, the history goes from the oldest game to the last one
+type roleCandidate struct {
+	preference RolePreference
+	history    []string
+}
+
+// civilianStreak counts the last games in a row the player has been a civilian
+func (rc roleCandidate) civilianStreak() int {
+	streak := 0
+	for i := len(rc.history) - 1; i >= 0 && rc.history[i] == CIVILIAN; i-- {
+		streak++
+	}
+	return streak
+}
+
+// weight tells how likely the player is to get the special role compared to the others
+func (rc roleCandidate) weight(role string) float64 {
+	weight := 1 + STREAK_WEIGHT*float64(rc.civilianStreak())
+	if len(rc.history) > 0 && sameRole(rc.history[len(rc.history)-1], role) {
+		weight *= REPEAT_WEIGHT
+	}
+
+	// wishing to be a civilian is the same as avoiding every special role and the other way round
+	if sameRole(rc.preference.Prefer, role) || rc.preference.Avoid == CIVILIAN {
+		weight *= PREFERENCE_WEIGHT
+	}
+	if sameRole(rc.preference.Avoid, role) || rc.preference.Prefer == CIVILIAN {
+		weight /= PREFERENCE_WEIGHT
+	}
+
+	if weight > ROLE_WEIGHT_LIM {
+		return ROLE_WEIGHT_LIM
+	} else if weight < 1/ROLE_WEIGHT_LIM {
+		return 1 / ROLE_WEIGHT_LIM
+	}
+	return weight
+}
+
+// sameRole treats the Don as a mafia member, so that wishing for the mafia covers both
+func sameRole(role, other string) bool {
+	if role == DON {
+		role = MAFIA
+	}
+	if other == DON {
+		other = MAFIA
+	}
+	return role != ANY_ROLE && role == other
+}
+
+// dealRoles gives every candidate exactly one role so that the counts match the composition:
+// the special roles are dealt one seat at a time in random order, each to a random player weighted
+// by their history and preferences, the ones left become civilians
+func dealRoles(c Composition, candidates []roleCandidate) []string {
+	var seats []string
+	for _, role := range roleOrder {
+		if role == CIVILIAN {
+			continue
+		}
+		for i := 0; i < c[role]; i++ {
+			seats = append(seats, role)
+		}
+	}
+	rand.Shuffle(len(seats), func(i, j int) { seats[i], seats[j] = seats[j], seats[i] })
+
+	roles := make([]string, len(candidates))
+	waiting := rand.Perm(len(candidates))
+	for _, role := range seats {
+		if len(waiting) == 0 {
+			break
+		}
+
+		total := 0.0
+		for _, ind := range waiting {
+			total += candidates[ind].weight(role)
+		}
+		pick, chosen := rand.Float64()*total, len(waiting)-1
+		for i, ind := range waiting {
+			if pick < candidates[ind].weight(role) {
+				chosen = i
+				break
+			}
+			pick -= candidates[ind].weight(role)
+		}
+
+		roles[waiting[chosen]] = role
+		waiting = append(waiting[:chosen], waiting[chosen+1:]...)
+	}
+	for _, ind := range waiting {
+		roles[ind] = CIVILIAN
+	}
+
+	return roles
+}
+
+// SetRolePreference remembers the player's wishes for the next games, they are kept by name across the rooms
+func (ms *mafiaSession) SetRolePreference(id uint64, pref RolePreference) error {
+	ms.lock.Lock()
+	player, ok := ms.players[id]
+	ms.lock.Unlock()
+	if !ok {
+		return playerRemovedError
+	}
+
+	return ms.roleRecords.setPreference(player.GetName(), pref)
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomCandidates makes players with random preferences and histories, so that every branch of the weights is hit
+func randomCandidates(rng *rand.Rand, n int) []roleCandidate {
+	wishes := append([]string{ANY_ROLE}, roleOrder...)
+	candidates := make([]roleCandidate, n)
+	for i := range candidates {
+		candidates[i].preference = RolePreference{Prefer: wishes[rng.Intn(len(wishes))], Avoid: wishes[rng.Intn(len(wishes))]}
+		for j := rng.Intn(ROLE_HISTORY_SIZE + 1); j > 0; j-- {
+			candidates[i].history = append(candidates[i].history, roleOrder[rng.Intn(len(roleOrder))])
+		}
+	}
+	return candidates
+}
+
+func TestDealRolesQuota(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for name, rules := range rulesetPresets(DefaultRuleset) {
+		for _, balance := range []string{BALANCE_TABLE, BALANCE_POWER} {
+			rules.Balance = balance
+			for n := PLAYERS_LOWER_LIM; n <= ROOM_SIZE_LIM; n++ {
+				for round := 0; round < 50; round++ {
+					composition := rules.balancedComposition(n)
+					total := 0
+					for role, count := range composition {
+						if count < 0 {
+							t.Fatalf("%s/%s, %d players: negative count of %s in %v", name, balance, n, role, composition)
+						}
+						total += count
+					}
+					if total != n || composition[CIVILIAN] < 1 || composition.mafiaTeam() < 1 {
+						t.Fatalf("%s/%s, %d players: bad composition %v", name, balance, n, composition)
+					}
+
+					roles := dealRoles(composition, randomCandidates(rng, n))
+					if len(roles) != n {
+						t.Fatalf("%s/%s, %d players: %d roles dealt", name, balance, n, len(roles))
+					}
+					dealt := Composition{}
+					for _, role := range roles {
+						dealt[role]++
+					}
+					for _, role := range roleOrder {
+						if dealt[role] != composition[role] {
+							t.Fatalf("%s/%s, %d players: dealt %v instead of %v", name, balance, n, dealt, composition)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRoleWeightLimits(t *testing.T) {
+	streak := roleCandidate{history: []string{CIVILIAN, CIVILIAN, CIVILIAN, CIVILIAN, CIVILIAN, CIVILIAN, CIVILIAN, CIVILIAN}}
+	if w := streak.weight(MAFIA); w != ROLE_WEIGHT_LIM {
+		t.Errorf("a long civilian streak should be capped at %v, got %v", ROLE_WEIGHT_LIM, w)
+	}
+
+	avoiding := roleCandidate{preference: RolePreference{Prefer: CIVILIAN, Avoid: MAFIA}, history: []string{DON}}
+	if w := avoiding.weight(MAFIA); w != 1/ROLE_WEIGHT_LIM {
+		t.Errorf("the odds of an avoided role should be kept above %v, got %v", 1/ROLE_WEIGHT_LIM, w)
+	}
+
+	fresh, wishing := roleCandidate{}, roleCandidate{preference: RolePreference{Prefer: DETECTIVE}}
+	if fresh.weight(DETECTIVE) >= wishing.weight(DETECTIVE) || wishing.weight(MAFIA) != fresh.weight(MAFIA) {
+		t.Errorf("a preference should raise the odds of only the preferred role")
+	}
+}
+
+func TestSetRolePreference(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	if err := ms.SetRolePreference(1, RolePreference{Prefer: MAFIA, Avoid: MAFIA}); err != preferenceConflictError {
+		t.Errorf("preferring and avoiding the same role should fail with %v, got %v", preferenceConflictError, err)
+	}
+	if err := ms.SetRolePreference(1, RolePreference{Prefer: DON}); err != nil {
+		t.Fatalf("couldn't set the preference: %v", err)
+	}
+	if pref := ms.roleRecords.preference("p1"); pref.Prefer != DON {
+		t.Errorf("the preference hasn't been kept by name, got %v", pref)
+	}
+
+	ms.RemovePlayer(1)
+	if err := ms.SetRolePreference(1, RolePreference{}); err != playerRemovedError {
+		t.Errorf("a removed player should get %v, got %v", playerRemovedError, err)
+	}
+}
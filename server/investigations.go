@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ---- how often a detective may reveal a finding to everyone
+const (
+	EXPOSE_NEVER = "never"
+	// EXPOSE_ONCE allows a single reveal per game
+	EXPOSE_ONCE = "once"
+	// EXPOSE_DAILY allows one reveal a day
+	EXPOSE_DAILY = "daily"
+	EXPOSE_ANY   = "any"
+)
+
+// revealRecord is a finding a detective has revealed to everyone
+type revealRecord struct {
+	round     int
+	detective string
+	target    string
+}
+
+// visibleChecks returns the checks the player may read: their own ones and, if the detectives share results, the other detectives' ones;
+// the caller holds the lock
+func (ms *mafiaSession) visibleChecks(id uint64) []checkRecord {
+	player := ms.players[id]
+	shared := ms.rules.ShareChecks && ms.dealtRole(player) == DETECTIVE
+	var res []checkRecord
+	for _, check := range ms.checks {
+		if check.checker == player.GetName() || (shared && check.role == DETECTIVE) {
+			res = append(res, check)
+		}
+	}
+	return res
+}
+
+// GetInvestigations returns the log of the checks the player may read in the current or the last game
+func (ms *mafiaSession) GetInvestigations(id uint64) ([]checkRecord, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if _, ok := ms.players[id]; !ok {
+		return nil, playerRemovedError
+	}
+	return ms.visibleChecks(id), nil
+}
+
+// shareCheck tells the other living detectives about the check if the ruleset lets them share results
+func (ms *mafiaSession) shareCheck(check checkRecord) {
+	if !ms.rules.ShareChecks {
+		return
+	}
+
+	for _, player := range ms.players {
+		if player.GetRole() == DETECTIVE && player.GetName() != check.checker {
+			player.Notify(Notification{CHECK_SHARED, fmt.Sprintf("%s@@%s@@%t", check.checker, check.target, check.found)})
+		}
+	}
+}
+
+// passRevealLimit checks how many findings the detective has already revealed against the ruleset; the caller holds the lock
+func (ms *mafiaSession) passRevealLimit(id uint64) bool {
+	player := ms.players[id]
+	revealed, today := 0, 0
+	for _, reveal := range ms.reveals {
+		if reveal.detective == player.GetName() {
+			revealed++
+			if reveal.round == ms.roundCnt+1 {
+				today++
+			}
+		}
+	}
+
+	if ms.rules.ExposeLimit == EXPOSE_NEVER {
+		player.Notify(Notification{VOTING_RESTRICTED, "findings can't be revealed in this game"})
+	} else if ms.rules.ExposeLimit == EXPOSE_ONCE && revealed > 0 {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already revealed a finding in this game"})
+	} else if ms.rules.ExposeLimit == EXPOSE_DAILY && today > 0 {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already revealed a finding today"})
+	} else {
+		return true
+	}
+
+	return false
+}
+
+// findingToReveal picks the check to reveal: the one of the given player or, without a name, the latest one that has found the mafia;
+// the caller holds the lock
+func (ms *mafiaSession) findingToReveal(id uint64, target string) (checkRecord, bool) {
+	checks := ms.visibleChecks(id)
+	for i := len(checks) - 1; i >= 0; i-- {
+		if checks[i].role != DETECTIVE {
+			continue
+		}
+		if (target == "" && checks[i].found) || (target != "" && checks[i].target == target) {
+			return checks[i], true
+		}
+	}
+	return checkRecord{}, false
+}
+
+// formatCheckShared renders the CHECK_SHARED info
+func formatCheckShared(info string) string {
+	parts := strings.SplitN(info, "@@", 3)
+	if len(parts) < 3 {
+		return info
+	}
+	if parts[2] == "true" {
+		return fmt.Sprintf("Detective %s has found out that '%s' is a member of Mafia", parts[0], parts[1])
+	}
+	return fmt.Sprintf("Detective %s has found out that '%s' is not a member of Mafia", parts[0], parts[1])
+}
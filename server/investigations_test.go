@@ -0,0 +1,24 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestInvestigationsOfRemovedPlayer(t *testing.T) {
+	rules := DefaultRuleset
+	rules.ShareChecks = true
+	ms := newTestGame(rules, DAY, MAFIA, DETECTIVE, DETECTIVE, CIVILIAN)
+	ms.checks = []checkRecord{{round: 1, checker: "p1", role: DETECTIVE, target: "p0", found: true}}
+
+	if checks, err := ms.GetInvestigations(2); err != nil || len(checks) != 1 {
+		t.Errorf("the shared check should be visible to the other detective, got %v and %v", checks, err)
+	}
+	if checks, err := ms.GetInvestigations(3); err != nil || len(checks) != 0 {
+		t.Errorf("a civilian shouldn't see the checks, got %v and %v", checks, err)
+	}
+
+	ms.RemovePlayer(2)
+	if _, err := ms.GetInvestigations(2); err != playerRemovedError {
+		t.Errorf("a removed player should get %v, got %v", playerRemovedError, err)
+	}
+}
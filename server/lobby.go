@@ -0,0 +1,210 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// inLobby tells whether players can get ready, be kicked and start the game, caller holds the lock
+func (ms *mafiaSession) inLobby() bool {
+	return ms.status == WAITING || ms.status == ENDED
+}
+
+// openLobby turns the finished game into a lobby where the players get ready for a rematch
+func (ms *mafiaSession) openLobby() {
+	ms.lock.Lock()
+	ms.ready = make(map[uint64]bool)
+	ms.kickVotes = make(map[string]map[uint64]bool)
+	ms.lock.Unlock()
+	ms.NotifyPlayers(Notification{eventType: LOBBY_OPEN}, ALL)
+}
+
+// assignHost makes the longest connected player the host if there is none, caller holds the lock
+func (ms *mafiaSession) assignHost() {
+	if _, ok := ms.players[ms.host]; ok && ms.hasHost {
+		return
+	}
+
+	ms.hasHost = false
+	for id := range ms.players {
+		if !ms.hasHost || id < ms.host {
+			ms.host, ms.hasHost = id, true
+		}
+	}
+	if ms.hasHost {
+		ms.NotifyPlayers(Notification{HOST_ASSIGNED, ms.players[ms.host].GetName()}, ALL)
+	}
+}
+
+// setReady marks the player as ready or not for the next game, only possible in the lobby
+func (ms *mafiaSession) setReady(id uint64, ready bool) error {
+	ms.touch(id)
+	ms.lock.Lock()
+	player, ok := ms.players[id]
+	if !ok {
+		ms.lock.Unlock()
+		return playerRemovedError
+	}
+	if !ms.inLobby() {
+		ms.lock.Unlock()
+		return notInLobbyError
+	}
+	if ms.ready[id] == ready {
+		ms.lock.Unlock()
+		return nil
+	}
+	if ready {
+		ms.ready[id] = true
+	} else {
+		delete(ms.ready, id)
+	}
+	readyCnt, total := len(ms.ready), len(ms.players)
+	ms.lock.Unlock()
+
+	event := PLAYER_READY
+	if !ready {
+		event = PLAYER_UNREADY
+	}
+	ms.NotifyPlayers(Notification{event, fmt.Sprintf("%s@@%d@@%d", player.GetName(), readyCnt, total)}, ALL)
+	return nil
+}
+
+func (ms *mafiaSession) PlayerReady(id uint64) error {
+	return ms.setReady(id, true)
+}
+
+func (ms *mafiaSession) PlayerUnready(id uint64) error {
+	return ms.setReady(id, false)
+}
+
+// RematchReady tells whether the rematch can start without the host: the game is over and everyone left in the lobby
+// is ready, the first game of the room is always started by the host
+func (ms *mafiaSession) RematchReady() bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if ms.status != ENDED || len(ms.players) < PLAYERS_LOWER_LIM {
+		return false
+	}
+	for id := range ms.players {
+		if !ms.ready[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// StartGame lets the host start the countdown once there are enough players and all of them are ready
+func (ms *mafiaSession) StartGame(id uint64) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if _, ok := ms.players[id]; !ok {
+		return playerRemovedError
+	} else if !ms.hasHost || ms.host != id {
+		return notHostError
+	} else if !ms.inLobby() {
+		return notInLobbyError
+	} else if len(ms.players) < PLAYERS_LOWER_LIM {
+		return notEnoughPlayersError
+	}
+	for playerId := range ms.players {
+		if !ms.ready[playerId] {
+			return notAllReadyError
+		}
+	}
+
+	return nil
+}
+
+// KickPlayer lets the host remove a player from the lobby, the id of the kicked player is returned
+func (ms *mafiaSession) KickPlayer(id uint64, target string) (uint64, error) {
+	ms.lock.Lock()
+	_, ok := ms.players[id]
+	targetId, err := ms.getPlayersIdByName(target)
+	if !ok {
+		ms.lock.Unlock()
+		return 0, playerRemovedError
+	} else if !ms.hasHost || ms.host != id {
+		ms.lock.Unlock()
+		return 0, notHostError
+	} else if ms.status != WAITING && ms.status != ENDED && ms.status != COUNTDOWN {
+		ms.lock.Unlock()
+		return 0, notInLobbyError
+	} else if err != nil {
+		ms.lock.Unlock()
+		return 0, playerNotFoundError
+	} else if targetId == id {
+		ms.lock.Unlock()
+		return 0, kickSelfError
+	}
+	ms.lock.Unlock()
+
+	log.Printf("Player %s has been kicked from the lobby", target)
+	ms.NotifyPlayers(Notification{PLAYER_KICKED, target}, ALL)
+	return targetId, ms.RemovePlayer(targetId)
+}
+
+// DropIdlePlayers removes the players of the finished game who haven't got ready for the rematch in time,
+// the ones who have joined the lobby after the game are left alone; the ids of the dropped players are returned
+func (ms *mafiaSession) DropIdlePlayers() []uint64 {
+	ms.lock.Lock()
+	idle := make(map[uint64]string)
+	if ms.status == ENDED {
+		for id, player := range ms.players {
+			if !ms.ready[id] && player.GetRole() != "" {
+				idle[id] = player.GetName()
+			}
+		}
+	}
+	ms.lock.Unlock()
+
+	var dropped []uint64
+	for id, name := range idle {
+		if ms.RemovePlayer(id) != nil {
+			// has disconnected in the meantime
+			continue
+		}
+		dropped = append(dropped, id)
+		log.Printf("Player %s has left the lobby", name)
+		ms.NotifyPlayers(Notification{CLIENT_DISCONNECTED, name}, ALL)
+	}
+	return dropped
+}
+
+// SetCountdown marks the session as about to start at the given time
+func (ms *mafiaSession) SetCountdown(deadline time.Time) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	ms.lobbyStatus = ms.status
+	ms.status = COUNTDOWN
+	ms.deadline = deadline
+}
+
+// CountdownBroken tells whether the countdown has to stop because too few players are left
+func (ms *mafiaSession) CountdownBroken() bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return ms.status == COUNTDOWN && len(ms.players) < PLAYERS_LOWER_LIM
+}
+
+// CancelCountdown brings the session back to the lobby it was in before the countdown
+func (ms *mafiaSession) CancelCountdown() {
+	ms.lock.Lock()
+	ms.status = ms.lobbyStatus
+	ms.lock.Unlock()
+	ms.NotifyPlayers(Notification{eventType: COUNTDOWN_CANCELLED}, ALL)
+}
+
+// resetPlayers clears everything left from the previous game before the roles are dealt again
+func (ms *mafiaSession) resetPlayers() {
+	for _, player := range ms.players {
+		player.Reset()
+	}
+	ms.lock.Lock()
+	ms.ready = make(map[uint64]bool)
+	ms.kickVotes = make(map[string]map[uint64]bool)
+	ms.dayStage = NOMINATION_STAGE
+	ms.accused = ""
+	ms.revoteCandidates = nil
+	ms.lock.Unlock()
+}
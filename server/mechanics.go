@@ -3,47 +3,116 @@ package server
 // ---- player roles
 const (
 	MAFIA     = "mafia"
+	DON       = "don"
 	DETECTIVE = "detective"
+	MANIAC    = "maniac"
+	JESTER    = "jester"
 	CIVILIAN  = "civilian"
 	GHOST     = "ghost"
 	ALL       = ""
 )
 
-// calcRoleQuota returns number of players for a certain role, based on number of players
-func calcRoleQuota(numberOfPlayers int, role string) int {
-	switch role {
-	case MAFIA:
-		if numberOfPlayers >= PLAYERS_LOWER_LIM && numberOfPlayers < PLAYERS_MID_LIM {
-			return 1
-		} else if numberOfPlayers >= PLAYERS_MID_LIM && numberOfPlayers < PLAYERS_UPPER_LIM {
-			return 2
-		} else if numberOfPlayers >= PLAYERS_UPPER_LIM {
-			return numberOfPlayers / 4
-		}
-	case DETECTIVE:
-		if numberOfPlayers >= PLAYERS_LOWER_LIM && numberOfPlayers < PLAYERS_UPPER_LIM {
-			return 1
-		} else if numberOfPlayers >= PLAYERS_UPPER_LIM {
-			return numberOfPlayers / 8
-		}
-	case CIVILIAN:
-		if numberOfPlayers >= PLAYERS_LOWER_LIM && numberOfPlayers < PLAYERS_MID_LIM {
-			return numberOfPlayers - 2
-		} else if numberOfPlayers >= PLAYERS_MID_LIM && numberOfPlayers < PLAYERS_UPPER_LIM {
-			return numberOfPlayers - 3
-		} else if numberOfPlayers >= PLAYERS_UPPER_LIM {
-			return numberOfPlayers - numberOfPlayers/4 - numberOfPlayers/8
-		}
-	default:
-		return -1
-	}
-
-	// < min number of players given
-	return -1
-}
+// ---- chat channels
+const (
+	PUBLIC_CHANNEL = "public"
+	MAFIA_CHANNEL  = "mafia"
+	GHOSTS_CHANNEL = "ghosts"
+	LOVERS_CHANNEL = "lovers"
+	DIRECT_CHANNEL = "direct"
+)
 
 // ---- phase shift
 const (
 	DAY = iota
 	NIGHT
 )
+
+// ---- session status
+const (
+	WAITING     = "waiting"
+	COUNTDOWN   = "countdown"
+	IN_PROGRESS = "in progress"
+	ENDED       = "ended"
+)
+
+// phaseName is the phase as it is shown to clients
+func phaseName(phase int) string {
+	if phase == NIGHT {
+		return "night"
+	}
+
+	return "day"
+}
+
+// ---- day procedures
+const (
+	// the most voted player is executed at the end of the day
+	PLURALITY_DAY = "plurality"
+	// players nominate candidates, every accused gets the last words and then a guilty/innocent verdict
+	TRIAL_DAY = "trial"
+)
+
+// ---- stages of a trial day
+const (
+	NOMINATION_STAGE = iota
+	DEFENCE_STAGE
+	VERDICT_STAGE
+)
+
+// stageName is the stage of a trial day as it is shown to clients
+func stageName(stage int) string {
+	switch stage {
+	case DEFENCE_STAGE:
+		return "defence"
+	case VERDICT_STAGE:
+		return "verdict"
+	}
+
+	return "nomination"
+}
+
+// ---- tie-breaking rules of the day vote
+const (
+	TIE_NO_EXECUTION = "none"
+	TIE_REVOTE       = "revote"
+	TIE_RANDOM       = "random"
+	TIE_EXECUTE_ALL  = "all"
+)
+
+// ---- how the mafia settles on the night victim
+const (
+	// every living mafia member has to propose the same victim
+	MAFIA_UNANIMOUS = "unanimous"
+	// the victim needs proposals from more than half of the living mafia
+	MAFIA_MAJORITY = "majority"
+	// the Don's proposal wins when the mafia disagrees
+	MAFIA_DON = "don"
+)
+
+// REPORTS_KEPT is how many reports of the finished games can be requested
+const REPORTS_KEPT = 20
+
+// ---- how much of a dead player's role is announced
+const (
+	REVEAL_FULL = "full"
+	// only the side is announced: town, mafia or neutral
+	REVEAL_TEAM = "team"
+	// roles stay hidden until the end of the game summary
+	REVEAL_NONE = "none"
+)
+
+// ---- what a player has died of
+const (
+	DEATH_EXECUTION  = "execution"
+	DEATH_MAFIA      = "mafia"
+	DEATH_MANIAC     = "maniac"
+	DEATH_HEARTBREAK = "heartbreak"
+	// the player has been removed for idling or by a kick vote
+	DEATH_AFK  = "idle"
+	DEATH_KICK = "kick"
+)
+
+// isMafia tells whether the role belongs to the mafia team
+func isMafia(role string) bool {
+	return role == MAFIA || role == DON
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenBucket limits how often a player can send messages, allowing short bursts
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.tokens += now.Sub(b.lastRefill).Seconds() * CHAT_RATE
+	if b.tokens > CHAT_BURST {
+		b.tokens = CHAT_BURST
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// chatModeration keeps chat rules shared by all players of the session, players are identified by name so that reconnecting doesn't reset them;
+// moderators are the clients who have connected with the moderator token, so the rights are lost with the connection
+type chatModeration struct {
+	moderators map[uint64]bool
+	muted      map[string]bool
+	buckets    map[string]*tokenBucket
+	filter     *regexp.Regexp
+	lock       sync.Mutex
+}
+
+func newChatModeration(bannedWords []string) *chatModeration {
+	cm := &chatModeration{
+		moderators: make(map[uint64]bool),
+		muted:      make(map[string]bool),
+		buckets:    make(map[string]*tokenBucket),
+	}
+
+	if len(bannedWords) > 0 {
+		quoted := make([]string, len(bannedWords))
+		for i, word := range bannedWords {
+			quoted[i] = regexp.QuoteMeta(word)
+		}
+		cm.filter = regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+	}
+
+	return cm
+}
+
+// loadWordFilter reads banned words from the file, one per line, lines starting with '#' are skipped
+func loadWordFilter(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		word := strings.TrimSpace(scanner.Text())
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+
+	return words, nil
+}
+
+func (cm *chatModeration) grantModerator(id uint64) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	cm.moderators[id] = true
+}
+
+func (cm *chatModeration) isModerator(id uint64) bool {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	return cm.moderators[id]
+}
+
+func (cm *chatModeration) setMuted(name string, muted bool) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	cm.muted[name] = muted
+}
+
+// check tells whether the player may send the message right now
+func (cm *chatModeration) check(name, msg string) error {
+	if strings.TrimSpace(msg) == "" {
+		return emptyChatMsgError
+	}
+	if utf8.RuneCountInString(msg) > CHAT_MAX_MSG_LEN {
+		return chatMsgTooLongError
+	}
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	if cm.muted[name] {
+		return playerMutedError
+	}
+
+	bucket, ok := cm.buckets[name]
+	if !ok {
+		bucket = &tokenBucket{tokens: CHAT_BURST, lastRefill: time.Now()}
+		cm.buckets[name] = bucket
+	}
+	if !bucket.take(time.Now()) {
+		return chatRateLimitError
+	}
+
+	return nil
+}
+
+// censor masks banned words with asterisks, parts of longer words are left intact
+func (cm *chatModeration) censor(msg string) string {
+	if cm.filter == nil {
+		return msg
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, match := range cm.filter.FindAllStringIndex(msg, -1) {
+		before, _ := utf8.DecodeLastRuneInString(msg[:match[0]])
+		after, _ := utf8.DecodeRuneInString(msg[match[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+
+		res.WriteString(msg[last:match[0]])
+		res.WriteString(strings.Repeat("*", utf8.RuneCountInString(msg[match[0]:match[1]])))
+		last = match[1]
+	}
+	res.WriteString(msg[last:])
+
+	return res.String()
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"mafia-core/proto"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestModeratorToken(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	s.opts.ModeratorToken = "secret"
+	if _, err := s.Connect(context.Background(), &proto.ClientInfo{Name: "mod", ModeratorToken: "guess"}); err != wrongModeratorTokenError {
+		t.Fatalf("a wrong token should fail with %v, got %v", wrongModeratorTokenError, err)
+	}
+	mod, err := s.Connect(context.Background(), &proto.ClientInfo{Name: "mod", ModeratorToken: "secret"})
+	if err != nil {
+		t.Fatalf("the moderator couldn't connect: %v", err)
+	}
+	player := connect(t, s, "player")
+
+	if _, err := s.Mute(context.Background(), &proto.ClientReq{Id: player, Target: &proto.ClientInfo{Name: "mod"}}); err != notModeratorError {
+		t.Errorf("a player without the token should fail with %v, got %v", notModeratorError, err)
+	}
+	if _, err := s.Mute(context.Background(), &proto.ClientReq{Id: mod, Target: &proto.ClientInfo{Name: "player"}}); err != nil {
+		t.Errorf("the moderator couldn't mute: %v", err)
+	}
+
+	// the rights go with the connection, not with the name
+	s.Disconnect(context.Background(), mod)
+	impostor := connect(t, s, "mod")
+	if _, err := s.Unmute(context.Background(), &proto.ClientReq{Id: impostor, Target: &proto.ClientInfo{Name: "player"}}); err != notModeratorError {
+		t.Errorf("a player taking the moderator's name should fail with %v, got %v", notModeratorError, err)
+	}
+}
+
+func TestChatRestrictionIsAnError(t *testing.T) {
+	ms := newTestGame(rulesetPresets(DefaultRuleset)[RULESET_CLASSIC], NIGHT, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	if err := ms.SendChatMsg(1, PUBLIC_CHANNEL, "", "hi"); err != nightChatError {
+		t.Errorf("talking at night should fail with %v, got %v", nightChatError, err)
+	}
+	if err := ms.SendChatMsg(1, MAFIA_CHANNEL, "", "hi"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("a civilian in the mafia channel should be denied, got %v", err)
+	}
+
+	ms.phase = DAY
+	if err := ms.SendChatMsg(1, DIRECT_CHANNEL, "p2", "hi"); err != directMessagesDisabledError {
+		t.Errorf("direct messages of the classic preset should fail with %v, got %v", directMessagesDisabledError, err)
+	}
+	if err := ms.SendChatMsg(1, PUBLIC_CHANNEL, "", "hi"); err != nil {
+		t.Errorf("couldn't talk during the day: %v", err)
+	}
+}
+
+func TestRefusedChatMsgKeepsRateLimit(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, NIGHT, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	for i := 0; i < 2*CHAT_BURST; i++ {
+		if err := ms.SendChatMsg(1, PUBLIC_CHANNEL, "", "hi"); err != nightChatError {
+			t.Fatalf("talking at night should fail with %v, got %v", nightChatError, err)
+		}
+	}
+
+	ms.phase = DAY
+	if err := ms.SendChatMsg(1, PUBLIC_CHANNEL, "", "hi"); err != nil {
+		t.Errorf("the refused messages have used up the rate limit: %v", err)
+	}
+}
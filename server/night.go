@@ -0,0 +1,258 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+// castMafiaProposal records the mafia member's proposed victim, it can be changed until the night ends
+func (ms *mafiaSession) castMafiaProposal(id uint64, target string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passTargetConditions(id, target) {
+		return
+	}
+
+	previous := ms.mafiaVotes[id]
+	ms.mafiaVotes[id] = target
+	if previous != target {
+		ms.NotifyPlayers(Notification{MAFIA_PROPOSAL, ms.players[id].GetName() + "@@" + target + "@@" + previous}, MAFIA)
+	}
+	ms.checkNightDone()
+}
+
+// castManiacTarget records the maniac's victim, it can be changed until the night ends
+func (ms *mafiaSession) castManiacTarget(id uint64, target string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passTargetConditions(id, target) {
+		return
+	}
+
+	ms.maniacTargets[id] = target
+	ms.players[id].Notify(Notification{MANIAC_TARGET, target})
+	ms.checkNightDone()
+}
+
+// investigate tells the detective whether the suspect is a member of the mafia, a detective checks one player a night
+func (ms *mafiaSession) investigate(id uint64, target string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passTargetConditions(id, target) {
+		return
+	}
+
+	detective := ms.players[id]
+	suspectId, _ := ms.getPlayersIdByName(target)
+	detective.SetActive(false)
+	ms.debug(fmt.Sprintf("Player %s checked %s", detective.GetName(), target))
+	suspect := ms.players[suspectId]
+	check := checkRecord{ms.roundCnt + 1, detective.GetName(), DETECTIVE, target, isMafia(suspect.GetRole())}
+	ms.checks = append(ms.checks, check)
+	if check.found {
+		detective.Notify(Notification{eventType: GUESS_SUCCESS})
+	} else {
+		detective.Notify(Notification{eventType: GUESS_FAIL})
+	}
+	ms.shareCheck(check)
+	ms.checkNightDone()
+}
+
+func (ms *mafiaSession) passCheckConditions(id uint64) bool {
+	player := ms.players[id]
+	_, donChecked := ms.donChecks[id]
+	if !ms.inProcess || ms.phase != NIGHT {
+		player.Notify(Notification{VOTING_RESTRICTED, "players may be checked only at night"})
+	} else if player.GetRole() != DON && player.GetRole() != DETECTIVE {
+		player.Notify(Notification{VOTING_RESTRICTED, "only the detective and the Don can check players"})
+	} else if (player.GetRole() == DETECTIVE && !player.IsActive()) || donChecked {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already checked a player tonight"})
+	} else {
+		return true
+	}
+
+	return false
+}
+
+// PlayerCheck is the night check: the detective looks for the mafia and the Don looks for the detective
+func (ms *mafiaSession) PlayerCheck(id uint64, target string) {
+	ms.touch(id)
+	if !ms.passCheckConditions(id) {
+		return
+	}
+
+	if ms.players[id].GetRole() == DETECTIVE {
+		ms.investigate(id, target)
+	} else {
+		ms.donInvestigate(id, target)
+	}
+}
+
+// donInvestigate privately tells the Don whether the suspect is the detective, the Don checks one player a night
+func (ms *mafiaSession) donInvestigate(id uint64, target string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passTargetConditions(id, target) {
+		return
+	}
+
+	don := ms.players[id]
+	suspectId, _ := ms.getPlayersIdByName(target)
+	ms.donChecks[id] = target
+	found := ms.players[suspectId].GetRole() == DETECTIVE
+	ms.checks = append(ms.checks, checkRecord{ms.roundCnt + 1, don.GetName(), DON, target, found})
+	if found {
+		don.Notify(Notification{DON_CHECK_SUCCESS, target})
+	} else {
+		don.Notify(Notification{DON_CHECK_FAIL, target})
+	}
+	ms.checkNightDone()
+}
+
+// mafiaConsensus returns the victim all living mafia members agree on, if they do
+func (ms *mafiaSession) mafiaConsensus() (string, bool) {
+	victim := ""
+	for id, player := range ms.players {
+		if !isMafia(player.GetRole()) {
+			continue
+		}
+		proposal, ok := ms.mafiaVotes[id]
+		if !ok || (victim != "" && proposal != victim) {
+			return "", false
+		}
+		victim = proposal
+	}
+
+	return victim, victim != ""
+}
+
+// checkNightDone ends the night early once the mafia is unanimous, the maniac has chosen
+// and every detective and the Don have made their checks, if the rules allow it
+func (ms *mafiaSession) checkNightDone() {
+	if !ms.rules.EarlyNightEnd {
+		return
+	}
+	if _, agreed := ms.mafiaConsensus(); !agreed && ms.mafiaAlive() {
+		return
+	}
+	for id, player := range ms.players {
+		if _, chosen := ms.maniacTargets[id]; player.GetRole() == MANIAC && !chosen {
+			return
+		}
+		if player.GetRole() == DETECTIVE && player.IsActive() {
+			return
+		}
+		if _, checked := ms.donChecks[id]; player.GetRole() == DON && !checked {
+			return
+		}
+	}
+
+	select {
+	case ms.nightDone <- struct{}{}:
+	default:
+	}
+}
+
+// mafiaAlive tells whether there is anyone left to make the mafia's kill
+func (ms *mafiaSession) mafiaAlive() bool {
+	for _, player := range ms.players {
+		if isMafia(player.GetRole()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nightVictim picks the victim according to the mafia resolution rule, the second value tells how the choice was made
+func (ms *mafiaSession) nightVictim() (string, string) {
+	if victim, agreed := ms.mafiaConsensus(); agreed {
+		return victim, MAFIA_UNANIMOUS
+	}
+
+	mafiaCnt, donProposal := 0, ""
+	counts := make(map[string]int)
+	for id, player := range ms.players {
+		if !isMafia(player.GetRole()) {
+			continue
+		}
+		mafiaCnt++
+		if proposal, ok := ms.mafiaVotes[id]; ok {
+			counts[proposal]++
+			if player.GetRole() == DON {
+				donProposal = proposal
+			}
+		}
+	}
+
+	if ms.rules.MafiaResolution == MAFIA_DON && donProposal != "" {
+		return donProposal, MAFIA_DON
+	}
+	if ms.rules.MafiaResolution == MAFIA_MAJORITY || ms.rules.MafiaResolution == MAFIA_DON {
+		// without the Don the mafia falls back to the majority
+		for victim, votes := range counts {
+			if 2*votes > mafiaCnt {
+				return victim, MAFIA_MAJORITY
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// runNight lets the mafia discuss the victim and detectives make their checks until the night timer runs out
+func (ms *mafiaSession) runNight() {
+	ms.lock.Lock()
+	ms.mafiaVotes = make(map[uint64]string)
+	ms.donChecks = make(map[uint64]string)
+	ms.maniacTargets = make(map[uint64]string)
+	ms.nightDone = make(chan struct{}, 1)
+	ms.deadline = time.Now().Add(ms.rules.NightTime)
+	ms.lock.Unlock()
+
+	nightStart := time.Now()
+	ms.NotifyPlayers(Notification{eventType: PHASE_START_NIGHT}, ALL)
+	ms.debug("WAITING ON NIGHT VOTES")
+	select {
+	case <-ms.nightDone:
+	case <-time.After(ms.rules.NightTime):
+	}
+	ms.checkNightIdlers(nightStart)
+
+	ms.lock.Lock()
+	victim, resolution := ms.nightVictim()
+	ms.recordNightActions()
+	var maniacVictims []string
+	for _, target := range ms.maniacTargets {
+		maniacVictims = append(maniacVictims, target)
+	}
+	ms.mafiaVotes = make(map[uint64]string)
+	ms.maniacTargets = make(map[uint64]string)
+	ms.lock.Unlock()
+
+	ms.mafiaKill(victim, resolution)
+	for _, target := range maniacVictims {
+		// the mafia may have already killed the maniac's victim
+		if targetId, err := ms.getPlayersIdByName(target); err == nil && ms.players[targetId].GetRole() != GHOST {
+			ms.delayedNotifications = append(ms.delayedNotifications, ms.eliminate(targetId, DEATH_MANIAC)...)
+		}
+	}
+}
+
+// mafiaKill eliminates the mafia's victim at the end of the night
+func (ms *mafiaSession) mafiaKill(victim, resolution string) {
+	if victim == "" {
+		ms.NotifyPlayers(Notification{eventType: MAFIA_VOTES_MISMATCH}, MAFIA)
+		return
+	}
+	victimId, err := ms.getPlayersIdByName(victim)
+	if err != nil {
+		ms.debug("NIGHT VICTIM ERROR")
+		ms.NotifyPlayers(Notification{PLAYER_NOT_FOUND, victim}, MAFIA)
+		return
+	}
+
+	ms.NotifyPlayers(Notification{MAFIA_DECISION, victim + "@@" + resolution}, MAFIA)
+	// Notification will be shown only at the beginning of the Next Day
+	ms.delayedNotifications = append(ms.delayedNotifications, ms.eliminate(victimId, DEATH_MAFIA)...)
+}
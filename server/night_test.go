@@ -0,0 +1,53 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestNightVictim(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		resolution string
+		votes      map[uint64]string
+		victim     string
+		how        string
+	}{
+		{"unanimous", MAFIA_UNANIMOUS, map[uint64]string{0: "p3", 1: "p3", 2: "p3"}, "p3", MAFIA_UNANIMOUS},
+		{"no consensus", MAFIA_UNANIMOUS, map[uint64]string{0: "p3", 1: "p3", 2: "p4"}, "", ""},
+		{"majority", MAFIA_MAJORITY, map[uint64]string{0: "p3", 1: "p3", 2: "p4"}, "p3", MAFIA_MAJORITY},
+		{"half isn't a majority", MAFIA_MAJORITY, map[uint64]string{0: "p3", 2: "p4"}, "", ""},
+		{"the don decides", MAFIA_DON, map[uint64]string{0: "p4", 1: "p3", 2: "p3"}, "p4", MAFIA_DON},
+		{"majority without the don's word", MAFIA_DON, map[uint64]string{1: "p3", 2: "p3"}, "p3", MAFIA_MAJORITY},
+	} {
+		rules := DefaultRuleset
+		rules.MafiaResolution = test.resolution
+		ms := newTestGame(rules, NIGHT, DON, MAFIA, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+		ms.mafiaVotes = test.votes
+		if victim, how := ms.nightVictim(); victim != test.victim || how != test.how {
+			t.Errorf("%s: got %q by %q, expected %q by %q", test.name, victim, how, test.victim, test.how)
+		}
+	}
+}
+
+func TestEarlyNightEnd(t *testing.T) {
+	for _, early := range []bool{false, true} {
+		rules := DefaultRuleset
+		rules.EarlyNightEnd = early
+		ms := newTestGame(rules, NIGHT, MAFIA, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+		ms.nightDone = make(chan struct{}, 1)
+		ms.mafiaVotes = map[uint64]string{0: "p2", 1: "p2"}
+		ms.players[4].SetActive(false)
+
+		ms.checkNightDone()
+		select {
+		case <-ms.nightDone:
+			if !early {
+				t.Errorf("the night has ended before its time without the early end")
+			}
+		default:
+			if early {
+				t.Errorf("the night goes on after everyone has acted")
+			}
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ---- notification queue overflow policies
+type OverflowPolicy uint8
+
+const (
+	// DROP_OLDEST removes the oldest non-critical notification to make room for the new one, if there is none the new one is skipped
+	DROP_OLDEST OverflowPolicy = iota
+	// DISCONNECT_SLOW closes the subscription of a player who doesn't keep up, they have to resubscribe
+	DISCONNECT_SLOW
+	// COALESCE replaces the notifications that didn't fit with a single note on how many were skipped
+	COALESCE
+)
+
+var overflowPolicyNames = map[string]OverflowPolicy{
+	"drop-oldest": DROP_OLDEST,
+	"disconnect":  DISCONNECT_SLOW,
+	"coalesce":    COALESCE,
+}
+
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	if policy, ok := overflowPolicyNames[name]; ok {
+		return policy, nil
+	}
+
+	return DROP_OLDEST, fmt.Errorf("unknown overflow policy '%s'", name)
+}
+
+// isCritical tells whether the event is never dropped, no matter how full the queue is
+func (e notificationEvent) isCritical() bool {
+	switch e {
+	case SESSION_START, SESSION_ABORT, SESSION_END, ROLE_ASSIGNED, PHASE_START_DAY, PHASE_START_NIGHT, PLAYER_ELIMINATED,
+		DEFENCE_START, VERDICT_START, VERDICT_RESULT, REVOTE_START:
+		return true
+	}
+
+	return false
+}
+
+// notificationMetrics counts how notifications were delivered across all sessions
+type notificationMetrics struct {
+	queued       atomic.Uint64
+	dropped      atomic.Uint64
+	coalesced    atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+var notificationStats notificationMetrics
+
+// WriteTo prints the metrics in Prometheus text format
+func (m *notificationMetrics) WriteTo(w io.Writer) (int64, error) {
+	n, err := fmt.Fprintf(w,
+		"# TYPE mafia_notifications_queued_total counter\nmafia_notifications_queued_total %d\n"+
+			"# TYPE mafia_notifications_dropped_total counter\nmafia_notifications_dropped_total %d\n"+
+			"# TYPE mafia_notifications_coalesced_total counter\nmafia_notifications_coalesced_total %d\n"+
+			"# TYPE mafia_slow_consumers_disconnected_total counter\nmafia_slow_consumers_disconnected_total %d\n",
+		m.queued.Load(), m.dropped.Load(), m.coalesced.Load(), m.disconnected.Load(),
+	)
+	return int64(n), err
+}
+
+// notificationRecord is a notification numbered in the order it was sent to the player
+type notificationRecord struct {
+	sequence uint64
+	event    Notification
+}
+
+// notificationQueue is a bounded mailbox of a single subscription, pushing to it never blocks the game
+type notificationQueue struct {
+	records    []notificationRecord
+	limit      int
+	backlog    int
+	policy     OverflowPolicy
+	overflowed bool
+	closed     bool
+	ready      chan struct{}
+	feed       *notificationFeed
+	lock       sync.Mutex
+}
+
+func (q *notificationQueue) wake() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *notificationQueue) push(record notificationRecord) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	defer q.wake()
+
+	if q.closed || q.overflowed {
+		notificationStats.dropped.Add(1)
+		return
+	}
+	if len(q.records) < q.limit || record.event.eventType.isCritical() {
+		q.records = append(q.records, record)
+		notificationStats.queued.Add(1)
+		return
+	}
+
+	switch q.policy {
+	case DROP_OLDEST:
+		notificationStats.dropped.Add(1)
+		for i, queued := range q.records {
+			if !queued.event.eventType.isCritical() && queued.event.eventType != NOTIFICATIONS_SKIPPED {
+				q.records = append(q.records[:i], q.records[i+1:]...)
+				q.records = append(q.records, record)
+				return
+			}
+		}
+		// everything queued is critical, so the new notification is the one to go and the player is told about it
+		q.skip(record)
+	case DISCONNECT_SLOW:
+		notificationStats.dropped.Add(1)
+		notificationStats.disconnected.Add(1)
+		q.overflowed = true
+	case COALESCE:
+		notificationStats.coalesced.Add(1)
+		q.skip(record)
+	}
+}
+
+// skip puts a note on the skipped notification at the end of the queue or adds it to the note already there, caller holds the lock
+func (q *notificationQueue) skip(record notificationRecord) {
+	// the note takes the number of the last skipped notification, so resubscribing after it doesn't bring them back
+	if last := len(q.records) - 1; last >= 0 && q.records[last].event.eventType == NOTIFICATIONS_SKIPPED {
+		skipped, _ := strconv.Atoi(q.records[last].event.info)
+		q.records[last] = notificationRecord{record.sequence, Notification{NOTIFICATIONS_SKIPPED, strconv.Itoa(skipped + 1)}}
+	} else {
+		q.records = append(q.records, notificationRecord{record.sequence, Notification{NOTIFICATIONS_SKIPPED, "1"}})
+	}
+}
+
+// pop waits for the next notification, a slow subscriber gets slowConsumerError and has to subscribe again
+func (q *notificationQueue) pop(ctx context.Context) (notificationRecord, error) {
+	for {
+		q.lock.Lock()
+		if q.overflowed {
+			q.lock.Unlock()
+			return notificationRecord{}, slowConsumerError
+		}
+		if len(q.records) > 0 {
+			record := q.records[0]
+			q.records = q.records[1:]
+			// history replayed on subscription doesn't count towards the limit
+			if q.backlog > 0 {
+				q.backlog--
+				q.limit--
+			}
+			q.lock.Unlock()
+			return record, nil
+		}
+		if q.closed {
+			q.lock.Unlock()
+			return notificationRecord{}, channelClosedError
+		}
+		q.lock.Unlock()
+
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return notificationRecord{}, ctx.Err()
+		}
+	}
+}
+
+func (q *notificationQueue) close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.closed = true
+	q.wake()
+}
+
+// cancel stops the subscription
+func (q *notificationQueue) cancel() {
+	q.feed.unsubscribe(q)
+	q.close()
+}
+
+// notificationFeed keeps the notification history of a player and fans every new notification out to all of their subscriptions
+type notificationFeed struct {
+	history     []notificationRecord
+	lastSeq     uint64
+	subscribers map[*notificationQueue]bool
+	policy      OverflowPolicy
+	closed      bool
+	lock        sync.Mutex
+}
+
+func newNotificationFeed(policy OverflowPolicy) *notificationFeed {
+	return &notificationFeed{
+		subscribers: make(map[*notificationQueue]bool),
+		policy:      policy,
+	}
+}
+
+func (f *notificationFeed) publish(event Notification) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return
+	}
+
+	f.lastSeq++
+	record := notificationRecord{f.lastSeq, event}
+	f.history = append(f.history, record)
+	if len(f.history) > NOTIFICATION_HISTORY_SIZE {
+		f.history = append(f.history[:0], f.history[1:]...)
+	}
+
+	for q := range f.subscribers {
+		q.push(record)
+	}
+}
+
+// subscribe starts a new subscription with every recorded notification after the given sequence number
+func (f *notificationFeed) subscribe(after uint64) *notificationQueue {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	q := &notificationQueue{
+		limit:  NOTIFICATION_QUEUE_SIZE,
+		policy: f.policy,
+		ready:  make(chan struct{}, 1),
+		feed:   f,
+		closed: f.closed,
+	}
+	for _, record := range f.history {
+		if record.sequence > after {
+			q.records = append(q.records, record)
+		}
+	}
+	q.backlog = len(q.records)
+	q.limit += q.backlog
+
+	if !f.closed {
+		f.subscribers[q] = true
+	}
+	return q
+}
+
+func (f *notificationFeed) unsubscribe(q *notificationQueue) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.subscribers, q)
+}
+
+// close ends all subscriptions, the ones made later are closed right away
+func (f *notificationFeed) close() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.closed = true
+	for q := range f.subscribers {
+		q.close()
+	}
+	f.subscribers = make(map[*notificationQueue]bool)
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+// fillQueue publishes the events in order to a fresh subscription of a feed with the policy and returns the subscription
+func fillQueue(policy OverflowPolicy, events ...notificationEvent) *notificationQueue {
+	feed := newNotificationFeed(policy)
+	q := feed.subscribe(0)
+	for _, event := range events {
+		feed.publish(Notification{event, ""})
+	}
+	return q
+}
+
+// drain pops everything queued, the error is the one that has stopped it
+func drain(q *notificationQueue) ([]notificationRecord, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var res []notificationRecord
+	record, err := q.pop(ctx)
+	for ; err == nil; record, err = q.pop(ctx) {
+		res = append(res, record)
+	}
+	return res, err
+}
+
+func TestDropOldestKeepsCritical(t *testing.T) {
+	events := []notificationEvent{SESSION_START}
+	for i := 0; i < NOTIFICATION_QUEUE_SIZE; i++ {
+		events = append(events, CLIENT_CONNECTED)
+	}
+	records, _ := drain(fillQueue(DROP_OLDEST, events...))
+	if len(records) != NOTIFICATION_QUEUE_SIZE || records[0].event.eventType != SESSION_START {
+		t.Fatalf("the critical notification should be kept and the queue bounded, got %v", records)
+	}
+	if records[1].sequence != 3 {
+		t.Errorf("the oldest ordinary notification should go first, the next one left is %d", records[1].sequence)
+	}
+}
+
+func TestDropOldestTellsAboutSkipped(t *testing.T) {
+	var events []notificationEvent
+	for i := 0; i < NOTIFICATION_QUEUE_SIZE; i++ {
+		events = append(events, PLAYER_ELIMINATED)
+	}
+	events = append(events, CLIENT_CONNECTED, CLIENT_DISCONNECTED, PHASE_START_DAY)
+	records, _ := drain(fillQueue(DROP_OLDEST, events...))
+
+	if len(records) != NOTIFICATION_QUEUE_SIZE+2 {
+		t.Fatalf("%d notifications instead of the critical ones and a note", len(records))
+	}
+	note := records[NOTIFICATION_QUEUE_SIZE]
+	if note.event.eventType != NOTIFICATIONS_SKIPPED || note.event.info != "2" || note.sequence != NOTIFICATION_QUEUE_SIZE+2 {
+		t.Errorf("the skipped notifications should be noted, got %v", note)
+	}
+	if last := records[len(records)-1]; last.event.eventType != PHASE_START_DAY {
+		t.Errorf("the critical notification after the note is lost, got %v", last)
+	}
+}
+
+func TestDisconnectSlow(t *testing.T) {
+	events := make([]notificationEvent, NOTIFICATION_QUEUE_SIZE+1)
+	records, err := drain(fillQueue(DISCONNECT_SLOW, events...))
+	if err != slowConsumerError || len(records) != 0 {
+		t.Errorf("the slow subscriber should be disconnected, got %d notifications and %v", len(records), err)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	events := make([]notificationEvent, NOTIFICATION_QUEUE_SIZE+3)
+	records, _ := drain(fillQueue(COALESCE, events...))
+	if len(records) != NOTIFICATION_QUEUE_SIZE+1 {
+		t.Fatalf("%d notifications instead of the ones that fit and a note", len(records))
+	}
+	if note := records[NOTIFICATION_QUEUE_SIZE]; note.event.eventType != NOTIFICATIONS_SKIPPED || note.event.info != "3" {
+		t.Errorf("the skipped notifications should be coalesced into a note, got %v", note)
+	}
+}
+
+func TestLateSubscriberCatchesUp(t *testing.T) {
+	feed := newNotificationFeed(DROP_OLDEST)
+	for i := 0; i < 3*NOTIFICATION_QUEUE_SIZE; i++ {
+		feed.publish(Notification{CLIENT_CONNECTED, ""})
+	}
+
+	// the history replayed on subscription isn't cut to the queue size
+	records, _ := drain(feed.subscribe(0))
+	if len(records) != 3*NOTIFICATION_QUEUE_SIZE || records[0].sequence != 1 {
+		t.Errorf("a new subscriber should get the whole history, got %d notifications", len(records))
+	}
+	records, _ = drain(feed.subscribe(25))
+	if len(records) != 3*NOTIFICATION_QUEUE_SIZE-25 || records[0].sequence != 26 {
+		t.Errorf("a resubscriber should get only what it has missed, got %v", records)
+	}
+
+	q := feed.subscribe(3 * NOTIFICATION_QUEUE_SIZE)
+	feed.publish(Notification{PHASE_START_DAY, ""})
+	if records, _ := drain(q); len(records) != 1 || records[0].event.eventType != PHASE_START_DAY {
+		t.Errorf("a caught up subscriber should get only the new notification, got %v", records)
+	}
+}
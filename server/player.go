@@ -1,5 +1,10 @@
 package server
 
+import (
+	"sync/atomic"
+	"time"
+)
+
 // MafiaPlayer interface describes possible actions of the mafia game session player
 type MafiaPlayer interface {
 	SetName(string)
@@ -10,24 +15,31 @@ type MafiaPlayer interface {
 	SetActive(bool)
 	// TODO: replace with pointer
 	Notify(Notification)
-	GetNotification() (Notification, error)
+	Subscribe(after uint64) *notificationQueue
 	CancelNotifications()
 	Vote(string)
 	WaitForVote() string
 	EndDay()
 	WaitEndDay() (string, bool)
-	SetExposed(string)
-	Expose() (string, error)
+	Reset()
+	// Touch records the time of the player's last command and forgets the missed phases
+	Touch()
+	LastAction() time.Time
+	// MissPhase counts one more phase in a row the player has been idle through
+	MissPhase() int
+	MissedPhases() int
 }
 
 type mafiaPlayer struct {
-	name                string
-	role                string
-	active              bool
-	notificationChannel chan Notification
-	voteChannel         chan string
-	endDayChannel       chan int
-	exposedPlayer       string
+	name          string
+	role          string
+	active        bool
+	notifications *notificationFeed
+	voteChannel   chan string
+	endDayChannel chan int
+	// lastAction is in unix nanoseconds, it is updated by the players' commands while the game loop reads it
+	lastAction   int64
+	missedPhases int32
 }
 
 func (p *mafiaPlayer) SetName(newName string) {
@@ -46,18 +58,6 @@ func (p *mafiaPlayer) GetRole() string {
 	return p.role
 }
 
-func (p *mafiaPlayer) SetExposed(player string) {
-	p.exposedPlayer = player
-}
-
-func (p *mafiaPlayer) Expose() (string, error) {
-	if p.exposedPlayer != "" {
-		return p.exposedPlayer, nil
-	}
-
-	return "", noExposedPlayerError
-}
-
 func (p *mafiaPlayer) SetActive(status bool) {
 	p.active = status
 }
@@ -66,24 +66,18 @@ func (p *mafiaPlayer) IsActive() bool {
 	return p.active
 }
 
+// Notify records the notification and passes it to every subscription without blocking, see OverflowPolicy for what happens when a subscriber doesn't keep up
 func (p *mafiaPlayer) Notify(msg Notification) {
-	p.notificationChannel <- msg
+	p.notifications.publish(msg)
 }
 
-// TODO: maybe replace with pointer to Notification
-func (p *mafiaPlayer) GetNotification() (Notification, error) {
-	event, ok := <-p.notificationChannel
-	if ok {
-		return event, nil
-	}
-
-	return event, channelClosedError
+// Subscribe returns a new subscription to the player's notifications, starting right after the given sequence number
+func (p *mafiaPlayer) Subscribe(after uint64) *notificationQueue {
+	return p.notifications.subscribe(after)
 }
 
 func (p *mafiaPlayer) CancelNotifications() {
-	close(p.notificationChannel)
-	close(p.voteChannel)
-	close(p.endDayChannel)
+	p.notifications.close()
 }
 
 func (p *mafiaPlayer) Vote(target string) {
@@ -108,3 +102,33 @@ func (p *mafiaPlayer) WaitEndDay() (string, bool) {
 		return "", true
 	}
 }
+
+// Reset forgets the role and everything left unread from the previous game
+func (p *mafiaPlayer) Reset() {
+	p.role = ""
+	p.active = false
+	atomic.StoreInt32(&p.missedPhases, 0)
+	for len(p.voteChannel) > 0 {
+		<-p.voteChannel
+	}
+	for len(p.endDayChannel) > 0 {
+		<-p.endDayChannel
+	}
+}
+
+func (p *mafiaPlayer) Touch() {
+	atomic.StoreInt64(&p.lastAction, time.Now().UnixNano())
+	atomic.StoreInt32(&p.missedPhases, 0)
+}
+
+func (p *mafiaPlayer) LastAction() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&p.lastAction))
+}
+
+func (p *mafiaPlayer) MissPhase() int {
+	return int(atomic.AddInt32(&p.missedPhases, 1))
+}
+
+func (p *mafiaPlayer) MissedPhases() int {
+	return int(atomic.LoadInt32(&p.missedPhases))
+}
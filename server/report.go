@@ -0,0 +1,534 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ---- formats a game report can be rendered in
+const (
+	REPORT_TEXT     = "text"
+	REPORT_MARKDOWN = "markdown"
+	REPORT_JSON     = "json"
+)
+
+// ---- kinds of day ballots
+const (
+	BALLOT_VOTE       = "vote"
+	BALLOT_REVOTE     = "revote"
+	BALLOT_NOMINATION = "nomination"
+	BALLOT_VERDICT    = "verdict"
+)
+
+// ballotRecord is a single day ballot, the target is empty for an abstention
+type ballotRecord struct {
+	round  int
+	kind   string
+	voter  string
+	target string
+	ballot string
+}
+
+// nightAction is a final night decision of a player, the checks are kept separately in checkRecord
+type nightAction struct {
+	round  int
+	actor  string
+	role   string
+	action string
+	target string
+}
+
+// GameReport is the post-mortem of a finished game
+type GameReport struct {
+	Id           string              `json:"id"`
+	StartedAt    time.Time           `json:"started_at"`
+	EndedAt      time.Time           `json:"ended_at"`
+	Duration     time.Duration       `json:"duration_ns"`
+	Rounds       int                 `json:"rounds"`
+	Winners      []string            `json:"winners"`
+	Players      []ReportPlayer      `json:"players"`
+	Eliminations []ReportElimination `json:"eliminations"`
+	Days         []ReportDay         `json:"days"`
+	Nights       []ReportNight       `json:"nights"`
+	Findings     []ReportFinding     `json:"detective_findings"`
+	MVP          *ReportMVP          `json:"mvp,omitempty"`
+}
+
+type ReportPlayer struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Alive bool   `json:"alive"`
+	Lover string `json:"lover,omitempty"`
+}
+
+type ReportElimination struct {
+	Round  int    `json:"round"`
+	Phase  string `json:"phase"`
+	Player string `json:"player"`
+	Role   string `json:"role"`
+	Cause  string `json:"cause"`
+}
+
+type ReportBallot struct {
+	Kind   string `json:"kind"`
+	Voter  string `json:"voter"`
+	Target string `json:"target,omitempty"`
+	// abstain for an abstention, guilty or innocent for a verdict
+	Ballot string `json:"ballot,omitempty"`
+}
+
+type ReportDay struct {
+	Round   int            `json:"round"`
+	Ballots []ReportBallot `json:"ballots"`
+}
+
+type ReportAction struct {
+	Actor  string `json:"actor"`
+	Role   string `json:"role"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Result string `json:"result,omitempty"`
+}
+
+type ReportNight struct {
+	Round   int            `json:"round"`
+	Actions []ReportAction `json:"actions"`
+}
+
+type ReportFinding struct {
+	Round     int    `json:"round"`
+	Detective string `json:"detective"`
+	Target    string `json:"target"`
+	Mafia     bool   `json:"mafia"`
+}
+
+type ReportMVP struct {
+	Name    string   `json:"name"`
+	Role    string   `json:"role"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// recordBallots remembers the current day votes before they are reset, caller holds the lock
+func (ms *mafiaSession) recordBallots(kind string) {
+	for id, target := range ms.dayVotes {
+		if player, ok := ms.players[id]; ok {
+			ms.ballots = append(ms.ballots, ballotRecord{ms.roundCnt + 1, kind, player.GetName(), target, ""})
+		}
+	}
+	for id := range ms.abstentions {
+		if player, ok := ms.players[id]; ok {
+			ms.ballots = append(ms.ballots, ballotRecord{ms.roundCnt + 1, kind, player.GetName(), "", "abstain"})
+		}
+	}
+}
+
+// recordNightActions remembers the final mafia proposals and maniac targets of the night, caller holds the lock
+func (ms *mafiaSession) recordNightActions() {
+	for id, target := range ms.mafiaVotes {
+		if player, ok := ms.players[id]; ok {
+			ms.nightActions = append(ms.nightActions, nightAction{ms.roundCnt + 1, player.GetName(), player.GetRole(), "proposed to kill", target})
+		}
+	}
+	for id, target := range ms.maniacTargets {
+		if player, ok := ms.players[id]; ok {
+			ms.nightActions = append(ms.nightActions, nightAction{ms.roundCnt + 1, player.GetName(), player.GetRole(), "attacked", target})
+		}
+	}
+}
+
+// buildReport puts together the report of the game that has just ended
+func (ms *mafiaSession) buildReport(winners []string) *GameReport {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	report := &GameReport{
+		Id:        ms.gameId,
+		StartedAt: ms.startedAt,
+		EndedAt:   time.Now(),
+		Rounds:    ms.roundCnt + 1,
+		Winners:   winners,
+	}
+	report.Duration = report.EndedAt.Sub(report.StartedAt).Round(time.Second)
+
+	for id, player := range ms.players {
+		role, dead := ms.graveyard[player.GetName()]
+		if !dead {
+			role = player.GetRole()
+		}
+		entry := ReportPlayer{Name: player.GetName(), Role: role, Alive: !dead}
+		if partnerId, ok := ms.partnerOf(id); ok {
+			entry.Lover = ms.players[partnerId].GetName()
+		}
+		report.Players = append(report.Players, entry)
+	}
+	sort.Slice(report.Players, func(i, j int) bool { return report.Players[i].Name < report.Players[j].Name })
+
+	for _, death := range ms.deaths {
+		report.Eliminations = append(report.Eliminations, ReportElimination{death.round, death.phase, death.victim, death.role, death.cause})
+	}
+
+	days := make(map[int]*ReportDay)
+	for _, ballot := range ms.ballots {
+		if days[ballot.round] == nil {
+			days[ballot.round] = &ReportDay{Round: ballot.round}
+		}
+		days[ballot.round].Ballots = append(days[ballot.round].Ballots, ReportBallot{ballot.kind, ballot.voter, ballot.target, ballot.ballot})
+	}
+	for _, day := range days {
+		// the kinds of ballots stay in the order they were cast in, the voters are sorted within each kind
+		kindOrder := make(map[string]int)
+		for _, ballot := range day.Ballots {
+			if _, ok := kindOrder[ballot.Kind]; !ok {
+				kindOrder[ballot.Kind] = len(kindOrder)
+			}
+		}
+		sort.SliceStable(day.Ballots, func(i, j int) bool {
+			if kindOrder[day.Ballots[i].Kind] != kindOrder[day.Ballots[j].Kind] {
+				return kindOrder[day.Ballots[i].Kind] < kindOrder[day.Ballots[j].Kind]
+			}
+			return day.Ballots[i].Voter < day.Ballots[j].Voter
+		})
+		report.Days = append(report.Days, *day)
+	}
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Round < report.Days[j].Round })
+
+	nights := make(map[int]*ReportNight)
+	addAction := func(round int, action ReportAction) {
+		if nights[round] == nil {
+			nights[round] = &ReportNight{Round: round}
+		}
+		nights[round].Actions = append(nights[round].Actions, action)
+	}
+	for _, action := range ms.nightActions {
+		addAction(action.round, ReportAction{Actor: action.actor, Role: action.role, Action: action.action, Target: action.target})
+	}
+	for _, check := range ms.checks {
+		result := "not mafia"
+		if check.role == DON && check.found {
+			result = "detective"
+		} else if check.role == DON {
+			result = "not detective"
+		} else if check.found {
+			result = "mafia"
+		}
+		addAction(check.round, ReportAction{check.checker, check.role, "checked", check.target, result})
+		if check.role == DETECTIVE {
+			report.Findings = append(report.Findings, ReportFinding{check.round, check.checker, check.target, check.found})
+		}
+	}
+	for _, night := range nights {
+		sort.SliceStable(night.Actions, func(i, j int) bool { return night.Actions[i].Actor < night.Actions[j].Actor })
+		report.Nights = append(report.Nights, *night)
+	}
+	sort.Slice(report.Nights, func(i, j int) bool { return report.Nights[i].Round < report.Nights[j].Round })
+
+	report.MVP = pickMVP(report)
+	return report
+}
+
+// pickMVP scores the players of the winning sides: surviving, voting against the other sides,
+// successful checks and kills that went through all count
+func pickMVP(report *GameReport) *ReportMVP {
+	roles := make(map[string]string)
+	for _, player := range report.Players {
+		roles[player.Name] = player.Role
+	}
+	won := func(player ReportPlayer) bool {
+		for _, winner := range report.Winners {
+			if winner == factionOf(player.Role) || (winner == LOVERS_FACTION && player.Lover != "") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var best *ReportMVP
+	for _, player := range report.Players {
+		if !won(player) {
+			continue
+		}
+
+		mvp := &ReportMVP{Name: player.Name, Role: player.Role}
+		if player.Alive {
+			mvp.Score += 3
+			mvp.Reasons = append(mvp.Reasons, "survived")
+		}
+		opposed := 0
+		for _, day := range report.Days {
+			for _, ballot := range day.Ballots {
+				if ballot.Voter != player.Name || ballot.Target == "" || ballot.Ballot == "innocent" {
+					continue
+				}
+				if target, ok := roles[ballot.Target]; ok && factionOf(target) != factionOf(player.Role) {
+					opposed++
+				}
+			}
+		}
+		if opposed > 0 {
+			mvp.Score += opposed
+			mvp.Reasons = append(mvp.Reasons, fmt.Sprintf("%d votes against the other sides", opposed))
+		}
+		found, kills := 0, 0
+		for _, night := range report.Nights {
+			for _, action := range night.Actions {
+				if action.Actor != player.Name {
+					continue
+				}
+				if action.Result == "mafia" || action.Result == "detective" {
+					found++
+				}
+				for _, death := range report.Eliminations {
+					if action.Action != "checked" && death.Round == night.Round && death.Phase == phaseName(NIGHT) && death.Player == action.Target {
+						kills++
+					}
+				}
+			}
+		}
+		if found > 0 {
+			mvp.Score += 2 * found
+			mvp.Reasons = append(mvp.Reasons, fmt.Sprintf("%d successful checks", found))
+		}
+		if kills > 0 {
+			mvp.Score += kills
+			mvp.Reasons = append(mvp.Reasons, fmt.Sprintf("%d kills", kills))
+		}
+
+		if best == nil || mvp.Score > best.Score {
+			best = mvp
+		}
+	}
+
+	return best
+}
+
+// storeReport keeps the report so that it can be requested by its game id, only the latest reports are kept
+func (ms *mafiaSession) storeReport(report *GameReport) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if ms.reports == nil {
+		ms.reports = make(map[string]*GameReport)
+	}
+	if _, stored := ms.reports[report.Id]; stored {
+		// the same game is only listed once
+		ms.reports[report.Id] = report
+		return
+	}
+	ms.reports[report.Id] = report
+	ms.reportIds = append(ms.reportIds, report.Id)
+	if len(ms.reportIds) > REPORTS_KEPT {
+		delete(ms.reports, ms.reportIds[0])
+		ms.reportIds = ms.reportIds[1:]
+	}
+}
+
+// GetGameReport returns the report of a finished game, the latest one if the id is empty
+func (ms *mafiaSession) GetGameReport(gameId string) (*GameReport, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if gameId == "" && len(ms.reportIds) > 0 {
+		gameId = ms.reportIds[len(ms.reportIds)-1]
+	}
+
+	report, ok := ms.reports[gameId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "there is no report for the game '%s'", gameId)
+	}
+	return report, nil
+}
+
+// renderReport turns the report into one of the REPORT_* formats
+func renderReport(report *GameReport, format string) (string, error) {
+	switch format {
+	case "", REPORT_TEXT:
+		return report.text(), nil
+	case REPORT_MARKDOWN, "md":
+		return report.markdown(), nil
+	case REPORT_JSON:
+		content, err := json.MarshalIndent(report, "", "  ")
+		return string(content), err
+	}
+
+	return "", status.Errorf(codes.InvalidArgument, "unknown report format '%s', expected %s, %s or %s", format, REPORT_TEXT, REPORT_MARKDOWN, REPORT_JSON)
+}
+
+func (report *GameReport) outcome() string {
+	return formatWinners(strings.Join(report.Winners, "@@"))
+}
+
+func (report *GameReport) text() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "==== Game %s ====\n", report.Id)
+	fmt.Fprintf(w, "Outcome:\t%s\n", report.outcome())
+	fmt.Fprintf(w, "Duration:\t%s, %d rounds\n", report.Duration, report.Rounds)
+	if report.MVP != nil {
+		fmt.Fprintf(w, "MVP:\t%s (%s): %s\n", report.MVP.Name, report.MVP.Role, strings.Join(report.MVP.Reasons, ", "))
+	}
+
+	fmt.Fprintln(w, "\nPlayers:")
+	for _, player := range report.Players {
+		status := "dead"
+		if player.Alive {
+			status = "alive"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s", player.Name, player.Role, status)
+		if player.Lover != "" {
+			fmt.Fprintf(w, "\tin love with %s", player.Lover)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(report.Eliminations) > 0 {
+		fmt.Fprintln(w, "\nEliminations:")
+		for _, death := range report.Eliminations {
+			fmt.Fprintf(w, "  %s %d\t%s (%s)\t%s\n", death.Phase, death.Round, death.Player, death.Role, describeDeath(death.Cause))
+		}
+	}
+	for _, day := range report.Days {
+		fmt.Fprintf(w, "\nDay %d votes:\n", day.Round)
+		for _, ballot := range day.Ballots {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", ballot.Voter, ballot.Kind, ballot.describe())
+		}
+	}
+	for _, night := range report.Nights {
+		fmt.Fprintf(w, "\nNight %d:\n", night.Round)
+		for _, action := range night.Actions {
+			fmt.Fprintf(w, "  %s (%s)\t%s\n", action.Actor, action.Role, action.describe())
+		}
+	}
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w, "\nDetective findings:")
+		for _, finding := range report.Findings {
+			fmt.Fprintf(w, "  night %d\t%s\t%s\n", finding.Round, finding.Detective, finding.describe())
+		}
+	}
+
+	w.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func (report *GameReport) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Game %s\n\n", report.Id)
+	fmt.Fprintf(&b, "- **Outcome:** %s\n", report.outcome())
+	fmt.Fprintf(&b, "- **Started:** %s\n", report.StartedAt.Format(time.RFC1123))
+	fmt.Fprintf(&b, "- **Duration:** %s, %d rounds\n", report.Duration, report.Rounds)
+	if report.MVP != nil {
+		fmt.Fprintf(&b, "- **MVP:** %s (%s): %s\n", report.MVP.Name, report.MVP.Role, strings.Join(report.MVP.Reasons, ", "))
+	}
+
+	b.WriteString("\n## Players\n\n| Player | Role | Status | Lover |\n|---|---|---|---|\n")
+	for _, player := range report.Players {
+		status := "dead"
+		if player.Alive {
+			status = "alive"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", player.Name, player.Role, status, player.Lover)
+	}
+
+	if len(report.Eliminations) > 0 {
+		b.WriteString("\n## Eliminations\n\n| Round | Player | Role | Cause |\n|---|---|---|---|\n")
+		for _, death := range report.Eliminations {
+			fmt.Fprintf(&b, "| %s %d | %s | %s | %s |\n", death.Phase, death.Round, death.Player, death.Role, describeDeath(death.Cause))
+		}
+	}
+
+	for _, day := range report.Days {
+		fmt.Fprintf(&b, "\n## Day %d\n", day.Round)
+		// one vote matrix per kind of ballot: voters in rows, targets in columns
+		for _, kind := range []string{BALLOT_NOMINATION, BALLOT_VOTE, BALLOT_REVOTE, BALLOT_VERDICT} {
+			var ballots []ReportBallot
+			var targets []string
+			seen := make(map[string]bool)
+			for _, ballot := range day.Ballots {
+				if ballot.Kind != kind {
+					continue
+				}
+				ballots = append(ballots, ballot)
+				if column := ballot.column(); !seen[column] {
+					seen[column] = true
+					targets = append(targets, column)
+				}
+			}
+			if len(ballots) == 0 {
+				continue
+			}
+
+			sort.Strings(targets)
+			fmt.Fprintf(&b, "\n**%s**\n\n| Voter | %s |\n|---|%s\n", kind, strings.Join(targets, " | "), strings.Repeat("---|", len(targets)))
+			for _, ballot := range ballots {
+				cells := make([]string, len(targets))
+				for i, target := range targets {
+					if target == ballot.column() {
+						cells[i] = ballot.mark()
+					}
+				}
+				fmt.Fprintf(&b, "| %s | %s |\n", ballot.Voter, strings.Join(cells, " | "))
+			}
+		}
+	}
+
+	if len(report.Nights) > 0 {
+		b.WriteString("\n## Night actions\n\n| Night | Player | Role | Action |\n|---|---|---|---|\n")
+		for _, night := range report.Nights {
+			for _, action := range night.Actions {
+				fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", night.Round, action.Actor, action.Role, action.describe())
+			}
+		}
+	}
+	if len(report.Findings) > 0 {
+		b.WriteString("\n## Detective findings\n\n")
+		for _, finding := range report.Findings {
+			fmt.Fprintf(&b, "- Night %d: %s %s\n", finding.Round, finding.Detective, finding.describe())
+		}
+	}
+
+	return b.String()
+}
+
+func (ballot ReportBallot) describe() string {
+	if ballot.Target == "" {
+		return "abstained"
+	} else if ballot.Kind == BALLOT_VERDICT {
+		return fmt.Sprintf("%s: %s", ballot.Target, ballot.Ballot)
+	}
+	return ballot.Target
+}
+
+// column is the vote matrix column of the ballot
+func (ballot ReportBallot) column() string {
+	if ballot.Target == "" {
+		return "abstained"
+	}
+	return ballot.Target
+}
+
+// mark is the vote matrix cell of the ballot
+func (ballot ReportBallot) mark() string {
+	if ballot.Kind == BALLOT_VERDICT {
+		return ballot.Ballot
+	}
+	return "x"
+}
+
+func (action ReportAction) describe() string {
+	if action.Result != "" {
+		return fmt.Sprintf("%s %s: %s", action.Action, action.Target, action.Result)
+	}
+	return action.Action + " " + action.Target
+}
+
+func (finding ReportFinding) describe() string {
+	if finding.Mafia {
+		return "found out that " + finding.Target + " is a member of the mafia"
+	}
+	return "cleared " + finding.Target
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStoreReportOnce(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.storeReport(&GameReport{Id: "first"})
+	ms.storeReport(&GameReport{Id: "second"})
+	ms.storeReport(&GameReport{Id: "second", Rounds: 3})
+
+	if len(ms.reportIds) != 2 {
+		t.Fatalf("the reports are listed as %v", ms.reportIds)
+	}
+	report, err := ms.GetGameReport("")
+	if err != nil || report.Id != "second" || report.Rounds != 3 {
+		t.Errorf("the latest report should be the one stored again, got %+v and %v", report, err)
+	}
+}
+
+func TestRenderReport(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.gameId = "game-1"
+	ms.eliminate(0, DEATH_EXECUTION)
+	report := ms.buildReport([]string{TOWN_FACTION})
+
+	text, err := renderReport(report, REPORT_TEXT)
+	if err != nil || !strings.Contains(text, "==== Game game-1 ====") || !strings.Contains(text, "p0") {
+		t.Errorf("unexpected text report %q, %v", text, err)
+	}
+	markdown, err := renderReport(report, "md")
+	if err != nil || !strings.HasPrefix(markdown, "# Game game-1") || !strings.Contains(markdown, "| Player | Role |") {
+		t.Errorf("unexpected markdown report %q, %v", markdown, err)
+	}
+	content, err := renderReport(report, REPORT_JSON)
+	var decoded GameReport
+	if err != nil || json.Unmarshal([]byte(content), &decoded) != nil {
+		t.Fatalf("the json report can't be read back: %v", err)
+	}
+	if decoded.Id != report.Id || len(decoded.Players) != 4 || len(decoded.Eliminations) != 1 {
+		t.Errorf("the json report differs from the original: %+v", decoded)
+	}
+
+	if _, err := renderReport(report, "pdf"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("an unknown format should be rejected, got %v", err)
+	}
+}
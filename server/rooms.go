@@ -0,0 +1,188 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log"
+	"sort"
+)
+
+// ---- room visibility
+const (
+	VISIBILITY_PUBLIC   = "public"
+	VISIBILITY_UNLISTED = "unlisted"
+)
+
+const (
+	// the main room has no invite code, players join it by default
+	MAIN_ROOM = ""
+	// invite codes skip the characters that are easy to confuse, like 0 and O
+	INVITE_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	INVITE_CODE_LEN      = 6
+)
+
+// RoomSettings are chosen by the player who creates a room, the main room has the zero ones
+type RoomSettings struct {
+	Code       string
+	Visibility string
+	// MaxPlayers is not limited if zero
+	MaxPlayers int
+	Ruleset    string
+	// passwordHash is empty if the room is open
+	passwordHash []byte
+}
+
+// RoomInfo is what is shown about a room in the list of rooms
+type RoomInfo struct {
+	RoomSettings
+	Players int
+	Status  string
+	Locked  bool
+}
+
+// room is a game session along with the channels its lobby is driven by
+type room struct {
+	session      MafiaSession
+	code         string
+	sessionStart chan int
+	countdownEnd chan int
+}
+
+func newRoom(session MafiaSession, code string) *room {
+	return &room{
+		session:      session,
+		code:         code,
+		sessionStart: make(chan int, 1),
+		countdownEnd: make(chan int, 1),
+	}
+}
+
+func hashPassword(password string) []byte {
+	if password == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(password))
+	return sum[:]
+}
+
+// Admit checks the password and the room capacity before a new player joins
+func (ms *mafiaSession) Admit(password string) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if len(ms.room.passwordHash) > 0 && subtle.ConstantTimeCompare(hashPassword(password), ms.room.passwordHash) != 1 {
+		return wrongPasswordError
+	} else if ms.room.MaxPlayers > 0 && len(ms.players) >= ms.room.MaxPlayers {
+		return roomFullError
+	}
+
+	return nil
+}
+
+func (ms *mafiaSession) GetRoomInfo() RoomInfo {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	return RoomInfo{
+		RoomSettings: ms.room,
+		Players:      len(ms.players),
+		Status:       ms.status,
+		Locked:       len(ms.room.passwordHash) > 0,
+	}
+}
+
+// roomOf finds the room of the player, the ones who have left or have been removed from their room have none
+func (s *server) roomOf(id uint64) (*room, error) {
+	s.roomsLock.RLock()
+	defer s.roomsLock.RUnlock()
+	if r, ok := s.clients[id]; ok {
+		return r, nil
+	}
+
+	return nil, playerRemovedError
+}
+
+// createRoom checks the settings and opens a new room with a fresh invite code
+func (s *server) createRoom(password, visibility string, maxPlayers int, ruleset string) (*room, error) {
+	if visibility == "" {
+		visibility = VISIBILITY_PUBLIC
+	}
+	if ruleset == "" {
+		ruleset = RULESET_SERVER
+	}
+	rules, ok := s.rulesets[ruleset]
+	if visibility != VISIBILITY_PUBLIC && visibility != VISIBILITY_UNLISTED {
+		return nil, unknownVisibilityError
+	} else if maxPlayers != 0 && (maxPlayers < PLAYERS_LOWER_LIM || maxPlayers > ROOM_SIZE_LIM) {
+		return nil, maxPlayersError
+	} else if !ok {
+		return nil, unknownRulesetError
+	}
+
+	s.roomsLock.Lock()
+	defer s.roomsLock.Unlock()
+	if len(s.rooms) > ROOMS_LIM {
+		return nil, tooManyRoomsError
+	}
+	code, err := s.newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	settings := RoomSettings{
+		Code:         code,
+		Visibility:   visibility,
+		MaxPlayers:   maxPlayers,
+		Ruleset:      ruleset,
+		passwordHash: hashPassword(password),
+	}
+	r := newRoom(s.newSession(rules, settings), code)
+	s.rooms[code] = r
+	log.Printf("Room %s has been created", code)
+	return r, nil
+}
+
+// newInviteCode picks a random code no other room has, caller holds the rooms lock
+func (s *server) newInviteCode() (string, error) {
+	buf := make([]byte, INVITE_CODE_LEN)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for i, b := range buf {
+			buf[i] = INVITE_CODE_ALPHABET[int(b)%len(INVITE_CODE_ALPHABET)]
+		}
+		if _, taken := s.rooms[string(buf)]; !taken {
+			return string(buf), nil
+		}
+	}
+}
+
+// closeRoom forgets the room once everyone has left it, the main room is never closed
+func (s *server) closeRoom(r *room) {
+	s.roomsLock.Lock()
+	defer s.roomsLock.Unlock()
+	delete(s.rooms, r.code)
+	for id, clientRoom := range s.clients {
+		if clientRoom == r {
+			delete(s.clients, id)
+		}
+	}
+	log.Printf("Room %s has been closed", r.code)
+}
+
+// publicRooms lists the rooms anyone can see, the main one goes first
+func (s *server) publicRooms() []RoomInfo {
+	s.roomsLock.RLock()
+	defer s.roomsLock.RUnlock()
+	var res []RoomInfo
+	for _, r := range s.rooms {
+		if info := r.session.GetRoomInfo(); info.Visibility != VISIBILITY_UNLISTED {
+			res = append(res, info)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+
+	return res
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"mafia-core/proto"
+	"strings"
+	"testing"
+)
+
+func TestRoomAdmission(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	if _, err := s.createRoom("", "hidden", 0, ""); err != unknownVisibilityError {
+		t.Errorf("an unknown visibility should fail with %v, got %v", unknownVisibilityError, err)
+	}
+	if _, err := s.createRoom("", "", ROOM_SIZE_LIM+1, ""); err != maxPlayersError {
+		t.Errorf("too many seats should fail with %v, got %v", maxPlayersError, err)
+	}
+	if _, err := s.createRoom("", "", 0, "custom"); err != unknownRulesetError {
+		t.Errorf("an unknown ruleset should fail with %v, got %v", unknownRulesetError, err)
+	}
+
+	r, err := s.createRoom("pw", VISIBILITY_UNLISTED, PLAYERS_LOWER_LIM, RULESET_TRIAL)
+	if err != nil {
+		t.Fatalf("couldn't create a room: %v", err)
+	}
+	for _, info := range s.publicRooms() {
+		if info.Code == r.code {
+			t.Errorf("the unlisted room is listed")
+		}
+	}
+
+	join := func(name, room, password string) error {
+		_, err := s.Connect(context.Background(), &proto.ClientInfo{Name: name, Room: room, Password: password})
+		return err
+	}
+	if err := join("a", "NOROOM", "pw"); err != roomNotFoundError {
+		t.Errorf("a wrong code should fail with %v, got %v", roomNotFoundError, err)
+	}
+	if err := join("a", r.code, "guess"); err != wrongPasswordError {
+		t.Errorf("a wrong password should fail with %v, got %v", wrongPasswordError, err)
+	}
+	for i := 0; i < PLAYERS_LOWER_LIM; i++ {
+		// the code is case-insensitive
+		if err := join(string(rune('a'+i)), " "+strings.ToLower(r.code), "pw"); err != nil {
+			t.Fatalf("couldn't join the room: %v", err)
+		}
+	}
+	if err := join("z", r.code, "pw"); err != roomFullError {
+		t.Errorf("joining a full room should fail with %v, got %v", roomFullError, err)
+	}
+	if cnt := s.rooms[MAIN_ROOM].session.GetPlayersCount(); cnt != 0 {
+		t.Errorf("%d players have ended up in the main room", cnt)
+	}
+}
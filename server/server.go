@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log"
 	"mafia-core/proto"
+	"math/rand"
 	"net"
 	"strings"
 	"sync"
@@ -15,192 +17,769 @@ import (
 
 type server struct {
 	proto.UnimplementedMafiaServer
-	session      MafiaSession
+	rooms        map[string]*room
+	clients      map[uint64]*room
+	rulesets     map[string]Ruleset
 	nextClientId uint64
-	sessionStart chan int
+	opts         Options
+	bannedWords  []string
+	roleRecords  *roleRecords
 	mutex        sync.Mutex
+	roomsLock    sync.RWMutex
 }
 
 func (s *server) Connect(_ context.Context, req *proto.ClientInfo) (*proto.ClientId, error) {
-	if s.session.HasStarted() {
-		return &proto.ClientId{Id: 42}, sessionStartedError
+	if req.Name == "" {
+		return &proto.ClientId{Id: 0}, emptyNameError
 	}
 
 	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.roomsLock.RLock()
+	r, ok := s.rooms[strings.ToUpper(strings.TrimSpace(req.Room))]
+	s.roomsLock.RUnlock()
+	if !ok {
+		return &proto.ClientId{Id: 0}, roomNotFoundError
+	}
+	if r.session.HasStarted() {
+		return &proto.ClientId{Id: 42}, sessionStartedError
+	}
+	if err := r.session.Admit(req.Password); err != nil {
+		return &proto.ClientId{Id: 0}, err
+	}
+	moderator := req.ModeratorToken != "" && s.opts.ModeratorToken != "" &&
+		subtle.ConstantTimeCompare([]byte(req.ModeratorToken), []byte(s.opts.ModeratorToken)) == 1
+	if req.ModeratorToken != "" && !moderator {
+		return &proto.ClientId{Id: 0}, wrongModeratorTokenError
+	}
+
 	clientId := s.nextClientId
-	err := s.session.AddPlayer(clientId, req.Name)
+	err := r.session.AddPlayer(clientId, req.Name)
 	if err != nil {
 		return &proto.ClientId{Id: 0}, err
 	}
-	s.session.NotifyPlayers(Notification{CLIENT_CONNECTED, s.session.GetPlayersName(clientId)}, ALL)
-	s.nextClientId++
-	if s.session.GetPlayersCount() == PLAYERS_LOWER_LIM {
-		s.sessionStart <- 1
+	s.roomsLock.Lock()
+	s.clients[clientId] = r
+	s.roomsLock.Unlock()
+	if moderator {
+		r.session.GrantModerator(clientId)
 	}
-	s.mutex.Unlock()
+	r.session.NotifyPlayers(Notification{CLIENT_CONNECTED, req.Name}, ALL)
+	s.nextClientId++
 	return &proto.ClientId{Id: clientId}, nil
 }
 
 func (s *server) Disconnect(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
-	s.session.NotifyPlayers(Notification{CLIENT_DISCONNECTED, s.session.GetPlayersName(req.Id)}, ALL)
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		// the room has been closed or the player has been removed from it
+		return &proto.EmptyMsg{}, err
+	}
+	name, err := r.session.GetPlayersName(req.Id)
+	if err != nil {
+		// the player has already been removed from the session, e.g. kicked from the lobby
+		s.forgetClient(req.Id)
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.NotifyPlayers(Notification{CLIENT_DISCONNECTED, name}, ALL)
 
 	s.mutex.Lock()
-	s.session.RemovePlayer(req.Id)
-	s.mutex.Unlock()
+	defer s.mutex.Unlock()
+	err = r.session.RemovePlayer(req.Id)
+	s.forgetClient(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if r.session.CountdownBroken() {
+		r.cancelCountdown()
+	} else if r.session.RematchReady() {
+		// the one who left might have been the only player not ready for the rematch
+		r.triggerStart()
+	}
 	return &proto.EmptyMsg{}, nil
 }
 
-func (s *server) SubscribeToNotifications(req *proto.ClientId, stream proto.Mafia_SubscribeToNotificationsServer) error {
-	event, err := s.session.GetPlayersNotifications(req.Id)
-	for ; err == nil; event, err = s.session.GetPlayersNotifications(req.Id) {
-		switch event.eventType {
-		case CLIENT_CONNECTED:
-			if err := stream.Send(&proto.Notification{Info: "Player " + event.info + " connected"}); err != nil {
-				return err
-			}
-		case CLIENT_DISCONNECTED:
-			if err := stream.Send(&proto.Notification{Info: "Player " + event.info + " disconnected"}); err != nil {
-				return err
-			}
-		case SESSION_DISCLAIMER:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("The game will start in %d seconds", START_DELAY/time.Second)}); err != nil {
-				return err
-			}
-		case SESSION_ABORT:
-			if err := stream.Send(&proto.Notification{Info: "There are not enough players to continue game, some of them might have disconnected"}); err != nil {
-				return err
-			}
-		case SESSION_START:
-			if err := stream.Send(&proto.Notification{Info: "---- GAME STARTED ----"}); err != nil {
-				return err
-			}
-		case SESSION_END:
-			if err := stream.Send(&proto.Notification{Info: "---- GAME ENDED ----\nThe outcome: " + event.info}); err != nil {
-				return err
-			}
-		case ROLE_ASSIGNED:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("You have been assigned the role of: %s", event.info)}); err != nil {
-				return err
-			}
-		case PLAYER_NOT_FOUND:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("There is no player with the name '%s' in the current session", event.info)}); err != nil {
-				return err
-			}
-		case PLAYER_EXPOSED:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("The Detective has found out that '%s' is a member of Mafia!", event.info)}); err != nil {
-				return err
-			}
-		case NO_EXPOSED_PLAYER:
-			if err := stream.Send(&proto.Notification{Info: "you haven't exposed anyone during last night"}); err != nil {
-				return err
-			}
-		case GUESS_SUCCESS:
-			if err := stream.Send(&proto.Notification{Info: "the selected player is a member of Mafia!"}); err != nil {
-				return err
-			}
-		case GUESS_FAIL:
-			if err := stream.Send(&proto.Notification{Info: "the selected player is not a member of Mafia"}); err != nil {
-				return err
-			}
-		case PLAYER_ELIMINATED:
-			nameRole := strings.Split(event.info, " ")
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("Player '%s' was a %s and has been eliminated. He may continue to observe the game session as a ghost", nameRole[0], nameRole[1])}); err != nil {
-				return err
-			}
-		case VOTING_RESTRICTED:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("Voting is restricted for you: %s", event.info)}); err != nil {
-				return err
-			}
-		case VOTES_MISMATCH:
-			if err := stream.Send(&proto.Notification{Info: "There wasn't a single target with the highest count of votes, so no-one is being executed"}); err != nil {
-				return err
-			}
-		case MAFIA_VOTES_MISMATCH:
-			if err := stream.Send(&proto.Notification{Info: "All mafia members have to vote for the same person, but there has been a mismatch"}); err != nil {
-				return err
-			}
-		case PHASE_START_DAY:
-			if err := stream.Send(&proto.Notification{Info: "---- A new day has started ----"}); err != nil {
-				return err
-			}
-		case PHASE_START_NIGHT:
-			if err := stream.Send(&proto.Notification{Info: "---- Darkness falls upon the city... ----"}); err != nil {
-				return err
-			}
-		case CHAT_MSG:
-			nameMsg := strings.Split(event.info, "@@")
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("%s -> : %s", nameMsg[0], nameMsg[1])}); err != nil {
-				return err
+// forgetClient drops the room mapping of a client who has left the session or has been removed from it
+func (s *server) forgetClient(id uint64) {
+	s.roomsLock.Lock()
+	delete(s.clients, id)
+	s.roomsLock.Unlock()
+}
+
+func (s *server) Ready(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if err := r.session.PlayerReady(req.Id); err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if r.session.RematchReady() {
+		r.triggerStart()
+	}
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Unready(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	return &proto.EmptyMsg{}, r.session.PlayerUnready(req.Id)
+}
+
+func (s *server) GetTeam(_ context.Context, req *proto.ClientId) (*proto.Team, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.Team{}, err
+	}
+	team, err := r.session.GetTeam(req.Id)
+	if err != nil {
+		return &proto.Team{}, err
+	}
+
+	res := &proto.Team{}
+	for _, player := range team {
+		res.Players = append(res.Players, &proto.PlayerState{Name: player.Name, Alive: player.Alive, Role: player.Role})
+	}
+	return res, nil
+}
+
+func (s *server) SetRolePreference(_ context.Context, req *proto.RolePreferenceReq) (*proto.EmptyMsg, error) {
+	pref := RolePreference{Prefer: strings.ToLower(strings.TrimSpace(req.Prefer)), Avoid: strings.ToLower(strings.TrimSpace(req.Avoid))}
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	return &proto.EmptyMsg{}, r.session.SetRolePreference(req.Id.Id, pref)
+}
+
+func (s *server) StartGame(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if err := r.session.StartGame(req.Id); err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.triggerStart()
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Kick(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	kickedId, err := r.session.KickPlayer(req.Id.Id, req.Target.Name)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	s.forgetClient(kickedId)
+	if r.session.CountdownBroken() {
+		r.cancelCountdown()
+	} else if r.session.RematchReady() {
+		r.triggerStart()
+	}
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) VoteKick(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	kickedId, removed, err := r.session.VoteKick(req.Id.Id, req.Target.Name)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if removed {
+		s.forgetClient(kickedId)
+	}
+	if r.session.CountdownBroken() {
+		r.cancelCountdown()
+	} else if r.session.RematchReady() {
+		r.triggerStart()
+	}
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) CreateRoom(_ context.Context, req *proto.RoomReq) (*proto.RoomInfo, error) {
+	r, err := s.createRoom(req.Password, req.Visibility, int(req.MaxPlayers), req.Ruleset)
+	if err != nil {
+		return &proto.RoomInfo{}, err
+	}
+	go s.ObserveSession(r)
+	return convertRoom(r.session.GetRoomInfo()), nil
+}
+
+func (s *server) ListRooms(context.Context, *proto.EmptyMsg) (*proto.RoomList, error) {
+	res := &proto.RoomList{}
+	for _, info := range s.publicRooms() {
+		res.Rooms = append(res.Rooms, convertRoom(info))
+	}
+
+	return res, nil
+}
+
+func convertRoom(info RoomInfo) *proto.RoomInfo {
+	return &proto.RoomInfo{
+		Code:       info.Code,
+		Visibility: info.Visibility,
+		MaxPlayers: uint32(info.MaxPlayers),
+		Ruleset:    info.Ruleset,
+		Players:    uint32(info.Players),
+		Status:     info.Status,
+		Locked:     info.Locked,
+	}
+}
+
+// formatNotification renders a session event as a human-readable message
+func formatNotification(event Notification) string {
+	switch event.eventType {
+	case CLIENT_CONNECTED:
+		return "Player " + event.info + " connected"
+	case CLIENT_DISCONNECTED:
+		return "Player " + event.info + " disconnected"
+	case SESSION_DISCLAIMER:
+		return fmt.Sprintf("The game will start in %d seconds", START_DELAY/time.Second)
+	case SESSION_ABORT:
+		return "There are not enough players to continue game, some of them might have disconnected"
+	case SESSION_START:
+		if event.info == "" {
+			return "---- GAME STARTED ----"
+		}
+		return "---- GAME STARTED ----\nRoles in the game: " + formatComposition(event.info)
+	case SESSION_END:
+		return "---- GAME ENDED ----\nThe outcome: " + formatWinners(event.info)
+	case ROLE_ASSIGNED:
+		return formatRoleAssignment(event.info)
+	case PLAYER_NOT_FOUND:
+		return fmt.Sprintf("There is no player with the name '%s' in the current session", event.info)
+	case PLAYER_EXPOSED:
+		return fmt.Sprintf("The Detective has found out that '%s' is a member of Mafia!", event.info)
+	case PLAYER_CLEARED:
+		return fmt.Sprintf("The Detective has found out that '%s' is not a member of Mafia", event.info)
+	case CHECK_SHARED:
+		return formatCheckShared(event.info)
+	case NO_EXPOSED_PLAYER:
+		return "you haven't found any member of Mafia yet"
+	case GUESS_SUCCESS:
+		return "the selected player is a member of Mafia!"
+	case GUESS_FAIL:
+		return "the selected player is not a member of Mafia"
+	case MANIAC_TARGET:
+		return fmt.Sprintf("You are going to kill %s tonight", event.info)
+	case LOVERS_LINKED:
+		return fmt.Sprintf("You are in love with %s, you share the lovers chat and if one of you dies, so does the other", event.info)
+	case LOBBY_OPEN:
+		return fmt.Sprintf("Type 'ready' to play again: the next game starts as soon as everyone is ready or the host starts it, "+
+			"players who aren't ready in %d seconds leave the lobby", REMATCH_TIMEOUT/time.Second)
+	case PLAYER_READY:
+		ready := strings.Split(event.info, "@@")
+		return fmt.Sprintf("%s is ready for the next game (%s/%s)", ready[0], ready[1], ready[2])
+	case PLAYER_UNREADY:
+		ready := strings.Split(event.info, "@@")
+		return fmt.Sprintf("%s is not ready anymore (%s/%s)", ready[0], ready[1], ready[2])
+	case HOST_ASSIGNED:
+		return fmt.Sprintf("%s is the host now and starts the game when everyone is ready", event.info)
+	case PLAYER_KICKED:
+		if strings.HasSuffix(event.info, "@@vote") {
+			return fmt.Sprintf("%s has been kicked from the lobby by a vote", strings.TrimSuffix(event.info, "@@vote"))
+		}
+		return fmt.Sprintf("%s has been kicked from the lobby by the host", event.info)
+	case KICK_VOTE:
+		vote := strings.Split(event.info, "@@")
+		return fmt.Sprintf("%s votes to kick %s (%s/%s votes needed)", vote[0], vote[1], vote[2], vote[3])
+	case AFK_WARNING:
+		return fmt.Sprintf("You seem to be away, the day will be skipped for you in %s seconds unless you do something", event.info)
+	case AFK_MISSED:
+		missed := strings.Split(event.info, "@@")
+		if missed[1] == "0" {
+			return fmt.Sprintf("You have missed %s phase(s) in a row", missed[0])
+		}
+		return fmt.Sprintf("You have missed %s phase(s) in a row, after %s you'll be removed from the game", missed[0], missed[1])
+	case AFK_SKIPPED:
+		return fmt.Sprintf("%s has been idle for too long and skips the day", event.info)
+	case COUNTDOWN_CANCELLED:
+		return "The countdown has been cancelled, there are not enough players left"
+	case GAME_REPORT:
+		return fmt.Sprintf("The full report of the game is available with 'report %s'", event.info)
+	case GAME_SUMMARY:
+		return formatSummary(event.info)
+	case JESTER_WON:
+		return fmt.Sprintf("%s was the Jester and wanted to be executed, the Jester has won!", event.info)
+	case DON_CHECK_SUCCESS:
+		return fmt.Sprintf("%s is the Detective!", event.info)
+	case DON_CHECK_FAIL:
+		return fmt.Sprintf("%s is not the Detective", event.info)
+	case PLAYER_ELIMINATED:
+		nameRole := strings.Split(event.info, " ")
+		death := "has been eliminated"
+		if len(nameRole) > 2 && nameRole[2] == DEATH_HEARTBREAK {
+			death = "has died of a broken heart"
+		} else if len(nameRole) > 2 && nameRole[2] == DEATH_AFK {
+			death = "has been removed for idling"
+		} else if len(nameRole) > 2 && nameRole[2] == DEATH_KICK {
+			death = "has been kicked by a vote"
+		}
+		return fmt.Sprintf("Player '%s' %s and %s. He may continue to observe the game session as a ghost", nameRole[0], describeRole(nameRole[1]), death)
+	case VOTING_RESTRICTED:
+		return fmt.Sprintf("Voting is restricted for you: %s", event.info)
+	case VOTES_MISMATCH:
+		return "There wasn't a single target with the highest count of votes, so no-one is being executed"
+	case MAFIA_VOTES_MISMATCH:
+		return "The mafia couldn't agree on the victim, so nobody is killed tonight"
+	case PHASE_START_DAY:
+		return "---- A new day has started ----"
+	case PHASE_START_NIGHT:
+		return "---- Darkness falls upon the city... ----"
+	case CHAT_MSG:
+		msg := strings.SplitN(event.info, "@@", 4)
+		switch msg[0] {
+		case PUBLIC_CHANNEL:
+			return fmt.Sprintf("%s -> : %s", msg[1], msg[3])
+		case DIRECT_CHANNEL:
+			return fmt.Sprintf("[%s] %s -> %s: %s", msg[0], msg[1], msg[2], msg[3])
+		default:
+			return fmt.Sprintf("[%s] %s -> : %s", msg[0], msg[1], msg[3])
+		}
+	case CHAT_RESTRICTED:
+		return fmt.Sprintf("You can't send message now: %s", event.info)
+	case PLAYER_MUTED:
+		return fmt.Sprintf("Player '%s' has been muted by a moderator", event.info)
+	case PLAYER_UNMUTED:
+		return fmt.Sprintf("Player '%s' can chat again", event.info)
+	case VOTE_CAST:
+		vote := strings.SplitN(event.info, "@@", 3)
+		if vote[2] != "" {
+			return fmt.Sprintf("%s changes the vote from %s to %s", vote[0], vote[2], vote[1])
+		}
+		return fmt.Sprintf("%s votes for %s", vote[0], vote[1])
+	case VOTE_RESULTS:
+		lines := []string{"Votes of the day:"}
+		for _, entry := range strings.Split(event.info, "\n") {
+			count := strings.SplitN(entry, "@@", 3)
+			if count[0] == "" {
+				count[0] = "abstained"
 			}
-		case CHAT_RESTRICTED:
-			if err := stream.Send(&proto.Notification{Info: fmt.Sprintf("You can't send message now: %s", event.info)}); err != nil {
-				return err
+			line := fmt.Sprintf("  %s: %s", count[0], count[1])
+			if count[2] != "" {
+				line += " (" + strings.ReplaceAll(count[2], ",", ", ") + ")"
 			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+	case MAFIA_PROPOSAL:
+		proposal := strings.SplitN(event.info, "@@", 3)
+		if proposal[2] != "" {
+			return fmt.Sprintf("%s changes the proposal from %s to %s", proposal[0], proposal[2], proposal[1])
+		}
+		return fmt.Sprintf("%s proposes to kill %s", proposal[0], proposal[1])
+	case MAFIA_DECISION:
+		decision := strings.SplitN(event.info, "@@", 2)
+		switch decision[1] {
+		case MAFIA_DON:
+			return fmt.Sprintf("The Don has decided: %s is killed tonight", decision[0])
+		case MAFIA_MAJORITY:
+			return fmt.Sprintf("The majority has decided: %s is killed tonight", decision[0])
+		}
+		return fmt.Sprintf("The mafia agreed: %s is killed tonight", decision[0])
+	case VOTE_ABSTAINED:
+		return fmt.Sprintf("%s abstains from the vote", event.info)
+	case NO_VOTES:
+		return "Nobody has voted, so no-one is being executed"
+	case REVOTE_START:
+		return fmt.Sprintf("The vote is tied between %s, vote again for one of them and skip when you are done", strings.ReplaceAll(event.info, "@@", ", "))
+	case TIE_BROKEN:
+		return fmt.Sprintf("The tie has been broken at random, %s is going to be executed", event.info)
+	case NOMINATION_MADE:
+		nomination := strings.SplitN(event.info, "@@", 2)
+		return fmt.Sprintf("%s nominates %s for execution", nomination[0], nomination[1])
+	case NO_NOMINATIONS:
+		return "Nobody has been nominated, so no-one is being executed today"
+	case DEFENCE_START:
+		trial := strings.SplitN(event.info, "@@", 2)
+		return fmt.Sprintf("%s is on trial and has %s seconds for the last words", trial[0], trial[1])
+	case VERDICT_START:
+		trial := strings.SplitN(event.info, "@@", 2)
+		return fmt.Sprintf("Vote guilty or innocent for %s, you have %s seconds", trial[0], trial[1])
+	case VERDICT_CAST:
+		verdict := strings.SplitN(event.info, "@@", 3)
+		return fmt.Sprintf("%s finds %s %s", verdict[0], verdict[1], verdict[2])
+	case VERDICT_RESULT:
+		result := strings.SplitN(event.info, "@@", 4)
+		return fmt.Sprintf("%s has been found %s (%s guilty, %s innocent)", result[0], result[1], result[2], result[3])
+	case NOTIFICATIONS_SKIPPED:
+		return fmt.Sprintf("%s notifications were skipped because you weren't receiving them fast enough", event.info)
+	}
+
+	return event.info
+}
+
+func (s *server) SubscribeToNotifications(req *proto.SubscribeReq, stream proto.Mafia_SubscribeToNotificationsServer) error {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return err
+	}
+	subscription, err := r.session.SubscribeToPlayersNotifications(req.Id, req.AfterSequence)
+	if err != nil {
+		return err
+	}
+	defer subscription.cancel()
+
+	record, err := subscription.pop(stream.Context())
+	for ; err == nil; record, err = subscription.pop(stream.Context()) {
+		notification := &proto.Notification{
+			Info:     formatNotification(record.event),
+			Event:    record.event.eventType.String(),
+			Data:     record.event.info,
+			Sequence: record.sequence,
+		}
+		if err := stream.Send(notification); err != nil {
+			return err
 		}
 	}
 
 	log.Printf("ClientId %d notification error: %v\n", req.Id, err)
+	if err == slowConsumerError {
+		return err
+	}
 	return nil
 }
 
-func (s *server) ShowPlayersList(context.Context, *proto.EmptyMsg) (*proto.PlayersList, error) {
-	return &proto.PlayersList{Players: s.session.GetConnectedPlayers()}, nil
+// ShowPlayersList takes ClientId, which is wire compatible with EmptyMsg old clients send
+func (s *server) ShowPlayersList(_ context.Context, req *proto.ClientId) (*proto.PlayersList, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.PlayersList{}, err
+	}
+	return &proto.PlayersList{Players: r.session.GetConnectedPlayers()}, nil
 }
 
 func (s *server) Vote(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
-	s.session.PlayerVote(req.Id.Id, req.Target.Name)
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerVote(req.Id.Id, req.Target.Name)
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Abstain(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerAbstain(req.Id)
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Check(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerCheck(req.Id.Id, req.Target.Name)
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Nominate(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerNominate(req.Id.Id, req.Target.Name)
+	return &proto.EmptyMsg{}, nil
+}
+
+func (s *server) Verdict(_ context.Context, req *proto.VerdictReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerVerdict(req.Id.Id, req.Guilty)
 	return &proto.EmptyMsg{}, nil
 }
 
 func (s *server) EndDay(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
-	s.session.PlayerEndDay(req.Id)
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerEndDay(req.Id)
 	return &proto.EmptyMsg{}, nil
 }
 
-func (s *server) Expose(_ context.Context, req *proto.ClientId) (*proto.EmptyMsg, error) {
-	s.session.PlayerExpose(req.Id)
+func (s *server) Expose(_ context.Context, req *proto.ExposeReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	r.session.PlayerExpose(req.Id, strings.TrimSpace(req.Target))
 	return &proto.EmptyMsg{}, nil
 }
 
+func (s *server) GetInvestigations(_ context.Context, req *proto.ClientId) (*proto.Investigations, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.Investigations{}, err
+	}
+	checks, err := r.session.GetInvestigations(req.Id)
+	if err != nil {
+		return &proto.Investigations{}, err
+	}
+
+	res := &proto.Investigations{}
+	for _, check := range checks {
+		res.Checks = append(res.Checks, &proto.Investigation{Round: uint32(check.round), Checker: check.checker, Role: check.role, Target: check.target, Found: check.found})
+	}
+	return res, nil
+}
+
 func (s *server) Chat(_ context.Context, req *proto.ChatMsg) (*proto.EmptyMsg, error) {
-	s.session.SendChatMsg(req.Id.Id, req.Msg)
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	if err := r.session.SendChatMsg(req.Id.Id, req.Channel, req.Recipient, req.Msg); err != nil {
+		return &proto.EmptyMsg{}, err
+	}
 	return &proto.EmptyMsg{}, nil
 }
 
-func (s *server) ObserveSession() {
+func (s *server) Mute(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	return &proto.EmptyMsg{}, r.session.SetPlayerMuted(req.Id.Id, req.Target.Name, true)
+}
+
+func (s *server) Unmute(_ context.Context, req *proto.ClientReq) (*proto.EmptyMsg, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.EmptyMsg{}, err
+	}
+	return &proto.EmptyMsg{}, r.session.SetPlayerMuted(req.Id.Id, req.Target.Name, false)
+}
+
+func (s *server) GetChatHistory(_ context.Context, req *proto.ChatHistoryReq) (*proto.ChatHistory, error) {
+	r, err := s.roomOf(req.Id.Id)
+	if err != nil {
+		return &proto.ChatHistory{}, err
+	}
+	history, err := r.session.GetChatHistory(req.Id.Id, req.Channel)
+	if err != nil {
+		return &proto.ChatHistory{}, err
+	}
+
+	res := &proto.ChatHistory{}
+	for _, message := range history {
+		res.Messages = append(res.Messages, &proto.ChatEntry{
+			Sender:    message.sender,
+			Channel:   message.channel,
+			Recipient: message.recipient,
+			Msg:       message.text,
+			Timestamp: message.sentAt.Unix(),
+		})
+	}
+
+	return res, nil
+}
+
+func (s *server) GetGameState(_ context.Context, req *proto.ClientId) (*proto.GameState, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.GameState{}, err
+	}
+	state, err := r.session.GetGameState(req.Id)
+	if err != nil {
+		return &proto.GameState{}, err
+	}
+
+	res := &proto.GameState{
+		Status:      state.Status,
+		Phase:       state.Phase,
+		Round:       uint32(state.Round),
+		SecondsLeft: uint32(state.TimeLeft.Round(time.Second).Seconds()),
+		Role:        state.Role,
+		Voted:       state.Voted,
+		Skipped:     state.Skipped,
+		Stage:       state.Stage,
+		Accused:     state.Accused,
+		Host:        state.Host,
+		Room:        state.Room,
+		Prefer:      state.Prefer,
+		Avoid:       state.Avoid,
+	}
+	for _, player := range state.Players {
+		res.Players = append(res.Players, &proto.PlayerState{Name: player.Name, Alive: player.Alive, Role: player.Role, Ready: player.Ready})
+	}
+	res.Tally = convertTally(state.Tally)
+	res.Abstainers = state.Abstainers
+
+	return res, nil
+}
+
+func convertTally(tally []VoteCount) []*proto.VoteCount {
+	var res []*proto.VoteCount
+	for _, count := range tally {
+		res = append(res, &proto.VoteCount{Target: count.Target, Votes: uint32(count.Votes), Voters: count.Voters})
+	}
+
+	return res
+}
+
+func (s *server) GetVoteTally(_ context.Context, req *proto.ClientId) (*proto.VoteTally, error) {
+	r, err := s.roomOf(req.Id)
+	if err != nil {
+		return &proto.VoteTally{}, err
+	}
+	tally, abstainers, err := r.session.GetVoteTally(req.Id)
+	if err != nil {
+		return &proto.VoteTally{}, err
+	}
+
+	return &proto.VoteTally{Tally: convertTally(tally), Abstainers: abstainers}, nil
+}
+
+func (s *server) GetGameReport(_ context.Context, req *proto.ReportReq) (*proto.Report, error) {
+	// the reports of the main room are open to anyone, the ones of a created room only to its players
+	s.roomsLock.RLock()
+	r := s.rooms[MAIN_ROOM]
+	s.roomsLock.RUnlock()
+	if req.Id != nil {
+		var err error
+		if r, err = s.roomOf(req.Id.Id); err != nil {
+			return &proto.Report{}, err
+		}
+	}
+	report, err := r.session.GetGameReport(req.GameId)
+	if err != nil {
+		return &proto.Report{}, err
+	}
+
+	content, err := renderReport(report, req.Format)
+	if err != nil {
+		return &proto.Report{}, err
+	}
+	return &proto.Report{GameId: report.Id, Format: req.Format, Content: content}, nil
+}
+
+// ObserveSession starts the games of the room, a created room is closed once everyone has left its lobby
+func (s *server) ObserveSession(r *room) {
 	for {
 		select {
-		case <-s.sessionStart:
-			for s.session.HasStarted() {
-				time.Sleep(START_DELAY)
+		case <-r.sessionStart:
+		case <-time.After(REMATCH_TIMEOUT):
+			s.mutex.Lock()
+			for _, id := range r.session.DropIdlePlayers() {
+				s.forgetClient(id)
+			}
+			abandoned := r.code != MAIN_ROOM && r.session.GetPlayersCount() == 0
+			if abandoned {
+				s.closeRoom(r)
+			}
+			s.mutex.Unlock()
+			if abandoned {
+				return
+			} else if !r.session.RematchReady() {
+				continue
 			}
-			// wait for extra players to join before starting game session
-			log.Println("Awaiting session start")
-			s.session.NotifyPlayers(Notification{eventType: SESSION_DISCLAIMER}, "")
+		}
+
+		for r.session.HasStarted() {
 			time.Sleep(START_DELAY)
-			s.session.Start()
 		}
+		// wait for extra players to join before starting game session
+		log.Println("Awaiting session start")
+		r.session.SetCountdown(time.Now().Add(START_DELAY))
+		r.session.NotifyPlayers(Notification{eventType: SESSION_DISCLAIMER}, "")
+		select {
+		case <-time.After(START_DELAY):
+			r.session.Start()
+		case <-r.countdownEnd:
+			r.session.CancelCountdown()
+		}
+	}
+}
+
+// cancelCountdown stops the countdown to the game start without blocking
+func (r *room) cancelCountdown() {
+	select {
+	case r.countdownEnd <- 1:
+	default:
+	}
+}
+
+// triggerStart wakes ObserveSession up without blocking, a pending start request is enough
+func (r *room) triggerStart() {
+	select {
+	case r.sessionStart <- 1:
+	default:
 	}
 }
 
-func Run(port int) {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+// newSession makes an empty session of a room with the server-wide moderation settings
+func (s *server) newSession(rules Ruleset, settings RoomSettings) *mafiaSession {
+	return &mafiaSession{
+		players:              make(map[uint64]MafiaPlayer),
+		status:               WAITING,
+		dayVotes:             make(map[uint64]string),
+		abstentions:          make(map[uint64]bool),
+		mafiaVotes:           make(map[uint64]string),
+		donChecks:            make(map[uint64]string),
+		maniacTargets:        make(map[uint64]string),
+		graveyard:            make(map[string]string),
+		ready:                make(map[uint64]bool),
+		kickVotes:            make(map[string]map[uint64]bool),
+		delayedNotifications: []Notification{},
+		rules:                rules,
+		room:                 settings,
+		moderation:           newChatModeration(s.bannedWords),
+		overflowPolicy:       s.opts.OverflowPolicy,
+		roleRecords:          s.roleRecords,
+	}
+}
+
+func Run(opts Options) {
+	var bannedWords []string
+	if opts.ChatFilterFile != "" {
+		words, err := loadWordFilter(opts.ChatFilterFile)
+		if err != nil {
+			log.Fatalf("failed to load chat filter: %v", err)
+		}
+		bannedWords = words
+	}
+
+	// the roles must not be the same from one server run to another
+	rand.Seed(time.Now().UnixNano())
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", opts.Port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 	servImpl := server{
-		session: &mafiaSession{
-			players:              make(map[uint64]MafiaPlayer),
-			potentialVictims:     make(map[string]int),
-			delayedNotifications: []Notification{},
-		},
+		clients:      make(map[uint64]*room),
+		rulesets:     rulesetPresets(opts.Rules),
 		nextClientId: 0,
-		sessionStart: make(chan int),
+		opts:         opts,
+		bannedWords:  bannedWords,
+		roleRecords:  newRoleRecords(),
 	}
+	mainRoom := newRoom(servImpl.newSession(opts.Rules, RoomSettings{Visibility: VISIBILITY_PUBLIC, Ruleset: RULESET_SERVER}), MAIN_ROOM)
+	servImpl.rooms = map[string]*room{MAIN_ROOM: mainRoom}
 	s := grpc.NewServer()
 	proto.RegisterMafiaServer(s, &servImpl)
 	log.Printf("SERVER listening at %v", listener.Addr())
-	go servImpl.ObserveSession()
+	go servImpl.ObserveSession(mainRoom)
+	go servImpl.ServeWeb(opts.WebPort)
 	if err := s.Serve(listener); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"mafia-core/proto"
+	"testing"
+)
+
+// newTestServer makes a server with the main room only, nothing is listening and no game loop is running
+func newTestServer(rules Ruleset) *server {
+	s := &server{
+		clients:     make(map[uint64]*room),
+		rulesets:    rulesetPresets(rules),
+		opts:        Options{Rules: rules},
+		roleRecords: newRoleRecords(),
+	}
+	s.rooms = map[string]*room{MAIN_ROOM: newRoom(s.newSession(rules, RoomSettings{Visibility: VISIBILITY_PUBLIC}), MAIN_ROOM)}
+	return s
+}
+
+// connect joins the main room of the test server under the name
+func connect(t *testing.T, s *server, name string) *proto.ClientId {
+	t.Helper()
+	id, err := s.Connect(context.Background(), &proto.ClientInfo{Name: name})
+	if err != nil {
+		t.Fatalf("%s couldn't connect: %v", name, err)
+	}
+	return id
+}
+
+func TestKickedPlayerDisconnects(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	host, guest := connect(t, s, "a"), connect(t, s, "b")
+
+	if _, err := s.Kick(context.Background(), &proto.ClientReq{Id: host, Target: &proto.ClientInfo{Name: "b"}}); err != nil {
+		t.Fatalf("the host couldn't kick: %v", err)
+	}
+	if _, ok := s.clients[guest.Id]; ok {
+		t.Errorf("the kicked client is still mapped to the room")
+	}
+	if _, err := s.Disconnect(context.Background(), guest); err != playerRemovedError {
+		t.Errorf("disconnecting the kicked client should fail with %v, got %v", playerRemovedError, err)
+	}
+	if _, err := s.Disconnect(context.Background(), host); err != nil {
+		t.Errorf("the host couldn't disconnect: %v", err)
+	}
+	if cnt := s.rooms[MAIN_ROOM].session.GetPlayersCount(); cnt != 0 {
+		t.Errorf("%d players are left in the room", cnt)
+	}
+}
+
+func TestVoteKickedPlayerDisconnects(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	ids := []*proto.ClientId{connect(t, s, "a"), connect(t, s, "b"), connect(t, s, "c"), connect(t, s, "d")}
+
+	// two votes out of three pass the default threshold of a half
+	for _, voter := range ids[1:3] {
+		if _, err := s.VoteKick(context.Background(), &proto.ClientReq{Id: voter, Target: &proto.ClientInfo{Name: "a"}}); err != nil {
+			t.Fatalf("couldn't vote to kick: %v", err)
+		}
+	}
+	if _, ok := s.clients[ids[0].Id]; ok {
+		t.Errorf("the kicked client is still mapped to the room")
+	}
+	if _, err := s.Disconnect(context.Background(), ids[0]); err != playerRemovedError {
+		t.Errorf("disconnecting the kicked client should fail with %v, got %v", playerRemovedError, err)
+	}
+	if cnt := s.rooms[MAIN_ROOM].session.GetPlayersCount(); cnt != 3 {
+		t.Errorf("%d players are left in the room instead of 3", cnt)
+	}
+}
+
+func TestDroppedPlayerDisconnects(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	ids := []*proto.ClientId{connect(t, s, "a"), connect(t, s, "b")}
+	ms := s.rooms[MAIN_ROOM].session.(*mafiaSession)
+	// both have played the last game, only a has got ready for the rematch
+	ms.status = ENDED
+	ms.players[ids[0].Id].SetRole(CIVILIAN)
+	ms.players[ids[1].Id].SetRole(CIVILIAN)
+	if err := ms.PlayerReady(ids[0].Id); err != nil {
+		t.Fatalf("couldn't get ready: %v", err)
+	}
+
+	for _, id := range ms.DropIdlePlayers() {
+		s.forgetClient(id)
+	}
+	if _, ok := s.clients[ids[1].Id]; ok {
+		t.Errorf("the dropped client is still mapped to the room")
+	}
+	if _, err := s.Disconnect(context.Background(), ids[1]); err != playerRemovedError {
+		t.Errorf("disconnecting the dropped client should fail with %v, got %v", playerRemovedError, err)
+	}
+	if _, ok := ms.players[ids[0].Id]; !ok {
+		t.Errorf("the ready player has been dropped")
+	}
+}
+
+func TestStragglerOfClosedRoomDisconnects(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	r, err := s.createRoom("", VISIBILITY_PUBLIC, 0, "")
+	if err != nil {
+		t.Fatalf("couldn't create a room: %v", err)
+	}
+	id, err := s.Connect(context.Background(), &proto.ClientInfo{Name: "a", Room: r.code})
+	if err != nil {
+		t.Fatalf("couldn't join the room: %v", err)
+	}
+
+	s.closeRoom(r)
+	if _, err := s.Disconnect(context.Background(), id); err != playerRemovedError {
+		t.Errorf("disconnecting from a closed room should fail with %v, got %v", playerRemovedError, err)
+	}
+	if _, err := s.Vote(context.Background(), &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: "a"}}); err != playerRemovedError {
+		t.Errorf("a client without a room shouldn't reach the main room, got %v", err)
+	}
+}
+
+func TestRematchStartsWhenEveryoneIsReady(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	r := s.rooms[MAIN_ROOM]
+	ids := []*proto.ClientId{connect(t, s, "a"), connect(t, s, "b"), connect(t, s, "c"), connect(t, s, "d")}
+	for _, id := range ids {
+		if _, err := s.Ready(context.Background(), id); err != nil {
+			t.Fatalf("couldn't get ready: %v", err)
+		}
+	}
+	if len(r.sessionStart) != 0 {
+		t.Fatalf("the first game should wait for the host")
+	}
+
+	r.session.(*mafiaSession).status = ENDED
+	for _, id := range ids[:3] {
+		s.Unready(context.Background(), id)
+		s.Ready(context.Background(), id)
+	}
+	if len(r.sessionStart) != 1 {
+		t.Errorf("the rematch hasn't started with everyone ready")
+	}
+}
@@ -4,58 +4,266 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MafiaSession interface {
 	Start()
 	PlayerVote(id uint64, target string)
 	PlayerEndDay(id uint64)
-	PlayerExpose(id uint64)
+	PlayerExpose(id uint64, target string)
 	AddPlayer(id uint64, name string) error
-	RemovePlayer(id uint64)
+	RemovePlayer(id uint64) error
 	GetPlayersRole(id uint64) string
 	SetPlayersRole(id uint64, role string)
-	GetPlayersName(id uint64) string
+	GetPlayersName(id uint64) (string, error)
 	SetPlayersName(id uint64, name string)
 	GetPlayersCount() int
 	GetConnectedPlayers() []string
 	HasStarted() bool
 	NotifyPlayers(msg Notification, role string)
-	SendChatMsg(id uint64, msg string)
-	GetPlayersNotifications(id uint64) (Notification, error)
+	SendChatMsg(id uint64, channel, recipient, msg string) error
+	SetPlayerMuted(id uint64, target string, muted bool) error
+	GrantModerator(id uint64)
+	GetChatHistory(id uint64, channel string) ([]ChatMessage, error)
+	SubscribeToPlayersNotifications(id uint64, after uint64) (*notificationQueue, error)
+	GetGameState(id uint64) (GameState, error)
+	GetVoteTally(id uint64) ([]VoteCount, []string, error)
+	GetGameReport(gameId string) (*GameReport, error)
+	PlayerAbstain(id uint64)
+	PlayerNominate(id uint64, target string)
+	PlayerCheck(id uint64, target string)
+	PlayerVerdict(id uint64, guilty bool)
+	PlayerReady(id uint64) error
+	PlayerUnready(id uint64) error
+	StartGame(id uint64) error
+	RematchReady() bool
+	KickPlayer(id uint64, target string) (uint64, error)
+	DropIdlePlayers() []uint64
+	CountdownBroken() bool
+	CancelCountdown()
+	SetCountdown(deadline time.Time)
 	UnsubscribePlayerFromNotifications(id uint64)
+	VoteKick(id uint64, target string) (uint64, bool, error)
+	Admit(password string) error
+	GetRoomInfo() RoomInfo
+	GetTeam(id uint64) ([]PlayerState, error)
+	GetInvestigations(id uint64) ([]checkRecord, error)
+	SetRolePreference(id uint64, pref RolePreference) error
 }
 
 type mafiaSession struct {
 	players              map[uint64]MafiaPlayer
 	inProcess            bool
+	status               string
+	deadline             time.Time
 	phase                int
-	mafiaAlive           int
-	civilianAlive        int
-	potentialVictims     map[string]int
+	dayVotes             map[uint64]string
+	abstentions          map[uint64]bool
+	revoteCandidates     []string
+	mafiaVotes           map[uint64]string
+	donChecks            map[uint64]string
+	maniacTargets        map[uint64]string
+	composition          Composition
+	extraWinners         []string
+	lovers               []uint64
+	deaths               []deathRecord
+	checks               []checkRecord
+	reveals              []revealRecord
+	ballots              []ballotRecord
+	nightActions         []nightAction
+	gameId               string
+	gameCnt              int
+	startedAt            time.Time
+	reports              map[string]*GameReport
+	reportIds            []string
+	ready                map[uint64]bool
+	kickVotes            map[string]map[uint64]bool
+	host                 uint64
+	hasHost              bool
+	lobbyStatus          string
+	nightDone            chan struct{}
+	graveyard            map[string]string
+	dayStage             int
+	nominees             []string
+	accused              string
+	verdicts             map[uint64]bool
+	verdictDone          chan struct{}
 	roundCnt             int
 	delayedNotifications []Notification
+	chatHistory          []ChatMessage
+	rules                Ruleset
+	room                 RoomSettings
+	moderation           *chatModeration
+	overflowPolicy       OverflowPolicy
+	roleRecords          *roleRecords
 	lock                 sync.Mutex
 	waitGr               sync.WaitGroup
 }
 
-func (ms *mafiaSession) SendChatMsg(id uint64, msg string) {
+// defaultChannel picks a channel for messages sent without one
+func (ms *mafiaSession) defaultChannel(id uint64) string {
 	if ms.players[id].GetRole() == GHOST {
-		ms.players[id].Notify(Notification{CHAT_RESTRICTED, "Ghosts are not allowed to send any messages"})
-		return
+		return GHOSTS_CHANNEL
+	} else if ms.phase == NIGHT {
+		return MAFIA_CHANNEL
 	}
 
-	if ms.phase == DAY {
-		ms.NotifyPlayers(Notification{CHAT_MSG, ms.players[id].GetName() + "@@" + msg}, ALL)
+	return PUBLIC_CHANNEL
+}
+
+// passChatConditions tells why the player can't send a message to the channel right now, nil if they can
+func (ms *mafiaSession) passChatConditions(id uint64, channel, recipient string) error {
+	player := ms.players[id]
+	recipientId, recipientErr := ms.getPlayersIdByName(recipient)
+	if channel != PUBLIC_CHANNEL && channel != MAFIA_CHANNEL && channel != GHOSTS_CHANNEL && channel != DIRECT_CHANNEL && channel != LOVERS_CHANNEL {
+		return status.Errorf(codes.InvalidArgument, "there is no '%s' channel", channel)
+	} else if channel != GHOSTS_CHANNEL && player.GetRole() == GHOST {
+		return ghostChannelOnlyError
+	} else if channel == GHOSTS_CHANNEL && player.GetRole() != GHOST {
+		return ghostsChannelError
+	} else if channel == MAFIA_CHANNEL && !isMafia(player.GetRole()) {
+		return mafiaChannelError
+	} else if channel == LOVERS_CHANNEL && !ms.isLover(id) {
+		return loversChannelError
+	} else if channel == PUBLIC_CHANNEL && ms.phase == NIGHT {
+		return nightChatError
+	} else if channel == PUBLIC_CHANNEL && ms.dayStage == DEFENCE_STAGE && player.GetName() != ms.accused {
+		return status.Errorf(codes.PermissionDenied, "only %s may speak during the last words", ms.accused)
+	} else if channel == DIRECT_CHANNEL && !ms.rules.DirectMessages {
+		return directMessagesDisabledError
+	} else if channel == DIRECT_CHANNEL && ms.phase == NIGHT {
+		return nightDirectMessageError
+	} else if channel == DIRECT_CHANNEL && recipientErr != nil {
+		return playerNotFoundError
+	} else if channel == DIRECT_CHANNEL && recipientId == id {
+		return directMessageSelfError
+	} else if channel == DIRECT_CHANNEL && ms.players[recipientId].GetRole() == GHOST {
+		return directMessageGhostError
+	}
+
+	return nil
+}
+
+// SendChatMsg delivers the message to everyone who can read the channel, the default one is chosen by the phase and sender's role
+func (ms *mafiaSession) SendChatMsg(id uint64, channel, recipient, msg string) error {
+	ms.touch(id)
+	player, ok := ms.players[id]
+	if !ok {
+		return playerRemovedError
+	}
+	if channel == "" {
+		channel = ms.defaultChannel(id)
+	}
+	if err := ms.passChatConditions(id, channel, recipient); err != nil {
+		return err
+	}
+	// a message refused by the rules of the channel doesn't count against the rate limit
+	if err := ms.moderation.check(player.GetName(), msg); err != nil {
+		return err
+	}
+	if channel != DIRECT_CHANNEL {
+		recipient = ""
+	}
+
+	message := ChatMessage{
+		sender:    ms.players[id].GetName(),
+		channel:   channel,
+		recipient: recipient,
+		text:      ms.moderation.censor(msg),
+		sentAt:    time.Now(),
+	}
+	ms.lock.Lock()
+	ms.chatHistory = append(ms.chatHistory, message)
+	ms.lock.Unlock()
+
+	notification := Notification{CHAT_MSG, message.encode()}
+	switch channel {
+	case PUBLIC_CHANNEL:
+		ms.NotifyPlayers(notification, ALL)
+	case MAFIA_CHANNEL:
+		ms.NotifyPlayers(notification, MAFIA)
+	case GHOSTS_CHANNEL:
+		ms.NotifyPlayers(notification, GHOST)
+	case LOVERS_CHANNEL:
+		for _, loverId := range ms.lovers {
+			ms.players[loverId].Notify(notification)
+		}
+	case DIRECT_CHANNEL:
+		recipientId, _ := ms.getPlayersIdByName(recipient)
+		ms.players[id].Notify(notification)
+		ms.players[recipientId].Notify(notification)
+	}
+
+	return nil
+}
+
+// GrantModerator lets the player mute others for as long as they stay connected
+func (ms *mafiaSession) GrantModerator(id uint64) {
+	ms.moderation.grantModerator(id)
+}
+
+// SetPlayerMuted mutes or unmutes the target in chat, only moderators are allowed to do that
+func (ms *mafiaSession) SetPlayerMuted(id uint64, target string, muted bool) error {
+	if _, ok := ms.players[id]; !ok {
+		return playerRemovedError
+	}
+	if !ms.moderation.isModerator(id) {
+		return notModeratorError
+	}
+	if _, err := ms.getPlayersIdByName(target); muted && err != nil {
+		return status.Errorf(codes.NotFound, "there is no player with the name '%s' in the current session", target)
+	}
+
+	ms.moderation.setMuted(target, muted)
+	if muted {
+		ms.NotifyPlayers(Notification{PLAYER_MUTED, target}, ALL)
 	} else {
-		if ms.players[id].GetRole() != MAFIA {
-			ms.players[id].Notify(Notification{CHAT_RESTRICTED, "only mafia can communicate at night"})
-			return
+		ms.NotifyPlayers(Notification{PLAYER_UNMUTED, target}, ALL)
+	}
+
+	return nil
+}
+
+func (ms *mafiaSession) canReadChat(id uint64, message ChatMessage) bool {
+	player := ms.players[id]
+	switch message.channel {
+	case PUBLIC_CHANNEL:
+		return true
+	case MAFIA_CHANNEL:
+		return isMafia(player.GetRole())
+	case GHOSTS_CHANNEL:
+		return player.GetRole() == GHOST
+	case LOVERS_CHANNEL:
+		return ms.isLover(id)
+	case DIRECT_CHANNEL:
+		return message.sender == player.GetName() || message.recipient == player.GetName()
+	}
+
+	return false
+}
+
+// GetChatHistory returns messages of the current game the player is allowed to read, all channels are included if none is given
+func (ms *mafiaSession) GetChatHistory(id uint64, channel string) ([]ChatMessage, error) {
+	if _, ok := ms.players[id]; !ok {
+		return nil, playerRemovedError
+	}
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	var history []ChatMessage
+	for _, message := range ms.chatHistory {
+		if (channel == "" || message.channel == channel) && ms.canReadChat(id, message) {
+			history = append(history, message)
 		}
-		ms.NotifyPlayers(Notification{CHAT_MSG, ms.players[id].GetName() + "@@" + msg}, MAFIA)
 	}
+
+	return history, nil
 }
 
 func (ms *mafiaSession) snapshot() {
@@ -64,9 +272,6 @@ func (ms *mafiaSession) snapshot() {
 		fmt.Sprintf("Players: %v\n", ms.players),
 		fmt.Sprintf("InProcess: %v\n", ms.inProcess),
 		fmt.Sprintf("Phase: %d\n", ms.phase),
-		fmt.Sprintf("mafiaAlive: %d\n", ms.mafiaAlive),
-		fmt.Sprintf("civilianAlive: %d\n", ms.civilianAlive),
-		fmt.Sprintf("potentialVictims: %v\n", ms.potentialVictims),
 		fmt.Sprintf("roundCnt: %d\n", ms.roundCnt),
 		fmt.Sprintf("delayedNotifications: %v\n", ms.delayedNotifications),
 	)
@@ -91,32 +296,51 @@ func (ms *mafiaSession) nameTaken(name string) bool {
 func (ms *mafiaSession) AddPlayer(id uint64, name string) error {
 	if !ms.nameTaken(name) {
 		ms.players[id] = &mafiaPlayer{
-			name:                name,
-			active:              false,
-			notificationChannel: make(chan Notification, 10),
-			voteChannel:         make(chan string, 1),
-			endDayChannel:       make(chan int, 1),
-			exposedPlayer:       "",
+			name:          name,
+			active:        false,
+			notifications: newNotificationFeed(ms.overflowPolicy),
+			voteChannel:   make(chan string, 1),
+			endDayChannel: make(chan int, 1),
 		}
+		ms.lock.Lock()
+		ms.assignHost()
+		ms.lock.Unlock()
 		return nil
 	}
 
 	return nameCollisionError
 }
 
-func (ms *mafiaSession) RemovePlayer(id uint64) {
-	if ms.players[id].GetRole() == MAFIA {
-		ms.mafiaAlive--
-	} else if ms.players[id].GetRole() == CIVILIAN {
-		ms.civilianAlive--
+func (ms *mafiaSession) RemovePlayer(id uint64) error {
+	ms.lock.Lock()
+	player, ok := ms.players[id]
+	if !ok {
+		ms.lock.Unlock()
+		return playerRemovedError
 	}
-
-	if ms.inProcess && ms.endGameConditionReached() {
-		ms.waitGr.Done()
-		ms.end()
+	delete(ms.mafiaVotes, id)
+	delete(ms.maniacTargets, id)
+	delete(ms.ready, id)
+	delete(ms.kickVotes, player.GetName())
+	for _, voters := range ms.kickVotes {
+		delete(voters, id)
 	}
-
+	if ms.isLover(id) {
+		// the link breaks when one of the lovers leaves
+		ms.lovers = nil
+	}
+	ms.lock.Unlock()
+	if ms.inProcess && player.GetRole() != GHOST {
+		// leaves the game the same way as an idle or a kicked player
+		player.SetRole(GHOST)
+		ms.withdrawPlayer(id)
+	}
+	player.CancelNotifications()
+	ms.lock.Lock()
 	delete(ms.players, id)
+	ms.assignHost()
+	ms.lock.Unlock()
+	return nil
 }
 
 func (ms *mafiaSession) getPlayersIdByName(name string) (uint64, error) {
@@ -137,9 +361,14 @@ func (ms *mafiaSession) SetPlayersRole(id uint64, role string) {
 	ms.players[id].SetRole(role)
 }
 
-func (ms *mafiaSession) GetPlayersName(id uint64) string {
-	//ms.snapshot()
-	return ms.players[id].GetName()
+func (ms *mafiaSession) GetPlayersName(id uint64) (string, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	player, ok := ms.players[id]
+	if !ok {
+		return "", playerRemovedError
+	}
+	return player.GetName(), nil
 }
 
 func (ms *mafiaSession) SetPlayersName(id uint64, name string) {
@@ -153,8 +382,8 @@ func (ms *mafiaSession) passVoteConditions(id uint64) bool {
 		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you are not allowed to vote on the first day"})
 	} else if ms.players[id].GetRole() == GHOST {
 		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you may only spectate as a ghost"})
-	} else if ms.phase == NIGHT && ms.players[id].GetRole() != MAFIA && ms.players[id].GetRole() != DETECTIVE {
-		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "only mafia members and detectives are allowed to vote at night"})
+	} else if ms.phase == NIGHT && !isMafia(ms.players[id].GetRole()) && ms.players[id].GetRole() != DETECTIVE && ms.players[id].GetRole() != MANIAC {
+		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "only mafia members, detectives and the maniac are allowed to vote at night"})
 	} else {
 		return true
 	}
@@ -162,9 +391,80 @@ func (ms *mafiaSession) passVoteConditions(id uint64) bool {
 	return false
 }
 
+// castDayVote records the vote for the running tally and announces it when voting is open
+func (ms *mafiaSession) castDayVote(id uint64, target string) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passTargetConditions(id, target) || !ms.passRevoteConditions(id, target) {
+		return false
+	}
+
+	previous := ms.dayVotes[id]
+	ms.dayVotes[id] = target
+	delete(ms.abstentions, id)
+	if ms.rules.OpenVoting && previous != target {
+		ms.NotifyPlayers(Notification{VOTE_CAST, ms.players[id].GetName() + "@@" + target + "@@" + previous}, ALL)
+	}
+
+	return true
+}
+
+func (ms *mafiaSession) passRevoteConditions(id uint64, target string) bool {
+	if len(ms.revoteCandidates) == 0 {
+		return true
+	}
+	for _, candidate := range ms.revoteCandidates {
+		if candidate == target {
+			return true
+		}
+	}
+
+	ms.players[id].Notify(Notification{VOTING_RESTRICTED, "only " + strings.Join(ms.revoteCandidates, ", ") + " are on the revote"})
+	return false
+}
+
+// PlayerAbstain withdraws the player's day vote and counts the player as abstained, unlike skip it doesn't end the player's day
+func (ms *mafiaSession) PlayerAbstain(id uint64) {
+	ms.touch(id)
+	if ms.phase != DAY || ms.rules.DayProcedure == TRIAL_DAY {
+		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you may abstain only from the day vote"})
+		return
+	}
+	if !ms.passVoteConditions(id) {
+		return
+	}
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if ms.abstentions[id] {
+		return
+	}
+	delete(ms.dayVotes, id)
+	ms.abstentions[id] = true
+	if ms.rules.OpenVoting {
+		ms.NotifyPlayers(Notification{VOTE_ABSTAINED, ms.players[id].GetName()}, ALL)
+	}
+}
+
 func (ms *mafiaSession) PlayerVote(id uint64, target string) {
+	ms.touch(id)
 	ms.debug("VOTE")
-	if ms.passVoteConditions(id) {
+	if ms.phase == DAY && ms.rules.DayProcedure == TRIAL_DAY {
+		// on a trial day a vote is a nomination, the verdict is voted on separately
+		ms.PlayerNominate(id, target)
+		return
+	}
+	if !ms.passVoteConditions(id) {
+		return
+	}
+
+	if ms.phase == NIGHT && ms.players[id].GetRole() == DETECTIVE {
+		ms.investigate(id, target)
+	} else if ms.phase == NIGHT && ms.players[id].GetRole() == MANIAC {
+		ms.castManiacTarget(id, target)
+	} else if ms.phase == NIGHT {
+		ms.castMafiaProposal(id, target)
+	} else if ms.castDayVote(id, target) {
 		ms.players[id].Vote(target)
 	}
 }
@@ -184,6 +484,7 @@ func (ms *mafiaSession) passEndDayConditions(id uint64) bool {
 }
 
 func (ms *mafiaSession) PlayerEndDay(id uint64) {
+	ms.touch(id)
 	ms.debug("EndDay")
 	if ms.passEndDayConditions(id) {
 		ms.players[id].EndDay()
@@ -196,8 +497,8 @@ func (ms *mafiaSession) passExposeConditions(id uint64) bool {
 		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you have already voted"})
 	} else if ms.players[id].GetRole() != DETECTIVE {
 		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "only detective can expose players"})
-	} else if ms.phase != DAY {
-		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you may expose players only at night"})
+	} else if !ms.inProcess || ms.phase != DAY {
+		ms.players[id].Notify(Notification{VOTING_RESTRICTED, "you may expose players only during the day"})
 	} else {
 		return true
 	}
@@ -205,16 +506,33 @@ func (ms *mafiaSession) passExposeConditions(id uint64) bool {
 	return false
 }
 
-func (ms *mafiaSession) PlayerExpose(id uint64) {
+// PlayerExpose reveals a finding of the detective to everyone, the latest found mafia member if no name is given
+func (ms *mafiaSession) PlayerExpose(id uint64, target string) {
+	ms.touch(id)
 	ms.debug("Expose")
-	if ms.passExposeConditions(id) {
-		exposedName, err := ms.players[id].Expose()
-		if err != nil {
-			log.Println(err.Error())
-			ms.players[id].Notify(Notification{eventType: NO_EXPOSED_PLAYER})
-		} else {
-			ms.NotifyPlayers(Notification{PLAYER_EXPOSED, exposedName}, ALL)
-		}
+	if !ms.passExposeConditions(id) {
+		return
+	}
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passRevealLimit(id) {
+		return
+	}
+	check, ok := ms.findingToReveal(id, target)
+	if !ok && target == "" {
+		ms.players[id].Notify(Notification{eventType: NO_EXPOSED_PLAYER})
+		return
+	} else if !ok {
+		ms.players[id].Notify(Notification{VOTING_RESTRICTED, fmt.Sprintf("you haven't checked '%s' in this game", target)})
+		return
+	}
+
+	ms.reveals = append(ms.reveals, revealRecord{ms.roundCnt + 1, ms.players[id].GetName(), check.target})
+	if check.found {
+		ms.NotifyPlayers(Notification{PLAYER_EXPOSED, check.target}, ALL)
+	} else {
+		ms.NotifyPlayers(Notification{PLAYER_CLEARED, check.target}, ALL)
 	}
 }
 
@@ -223,7 +541,7 @@ func (ms *mafiaSession) GetPlayersCount() int {
 }
 
 func (ms *mafiaSession) GetConnectedPlayers() []string {
-	res := make([]string, len(ms.players))
+	res := make([]string, 0, len(ms.players))
 	for _, player := range ms.players {
 		res = append(res, player.GetName())
 	}
@@ -231,13 +549,13 @@ func (ms *mafiaSession) GetConnectedPlayers() []string {
 	return res
 }
 
-func (ms *mafiaSession) GetPlayersNotifications(id uint64) (Notification, error) {
+func (ms *mafiaSession) SubscribeToPlayersNotifications(id uint64, after uint64) (*notificationQueue, error) {
 	player, ok := ms.players[id]
 	if ok {
-		return player.GetNotification()
+		return player.Subscribe(after), nil
 	}
 
-	return Notification{}, playerRemovedError
+	return nil, playerRemovedError
 }
 
 func (ms *mafiaSession) UnsubscribePlayerFromNotifications(id uint64) {
@@ -246,7 +564,7 @@ func (ms *mafiaSession) UnsubscribePlayerFromNotifications(id uint64) {
 
 func (ms *mafiaSession) NotifyPlayers(msg Notification, scope string) {
 	for _, player := range ms.players {
-		if scope == ALL || scope == player.GetRole() {
+		if scope == ALL || scope == player.GetRole() || (scope == MAFIA && isMafia(player.GetRole())) {
 			player.Notify(msg)
 		}
 	}
@@ -263,134 +581,165 @@ func (ms *mafiaSession) HasStarted() bool {
 	return ms.inProcess
 }
 
+// shuffleRoles deals the roles of a balanced composition, see dealRoles for how the players' history is taken into account
 func (ms *mafiaSession) shuffleRoles() {
-	var (
-		playerCnt              = len(ms.players)
-		mafiaCnt, detectiveCnt = calcRoleQuota(playerCnt, MAFIA), calcRoleQuota(playerCnt, DETECTIVE)
-	)
+	ms.composition = ms.rules.balancedComposition(len(ms.players))
+	ids := make([]uint64, 0, len(ms.players))
+	candidates := make([]roleCandidate, 0, len(ms.players))
+	for id, player := range ms.players {
+		ids = append(ids, id)
+		candidates = append(candidates, ms.roleRecords.candidate(player.GetName()))
+	}
 
-	shuffleOrder := rand.Perm(playerCnt)
-	var (
-		currentInd       = 0
-		nextMafiaInd     = 0
-		nextDetectiveInd = mafiaCnt
-		nextCivillianInd = mafiaCnt + detectiveCnt
-		curRole          = ""
-	)
+	for i, role := range dealRoles(ms.composition, candidates) {
+		player := ms.players[ids[i]]
+		player.SetRole(role)
+		ms.roleRecords.record(player.GetName(), role)
+	}
 
-	for _, player := range ms.players {
-		fmt.Println("INDEXES ARE AS FOLLOWING:\n",
-			fmt.Sprintf("\tcur ind %d\n", currentInd),
-			fmt.Sprintf("\tmafia %d %d\n", nextMafiaInd, mafiaCnt),
-			fmt.Sprintf("\tdetective %d %d\n", nextDetectiveInd, detectiveCnt),
-			fmt.Sprintf("\tcivillian %d %d\n", nextCivillianInd, playerCnt-mafiaCnt-detectiveCnt),
-			fmt.Sprintf("\tperm %v\n", shuffleOrder),
-		)
-		switch currentInd {
-		case shuffleOrder[nextMafiaInd]:
-			curRole = MAFIA
-			player.SetRole(MAFIA)
-			if nextMafiaInd < mafiaCnt-1 {
-				nextMafiaInd++
-			}
-		case shuffleOrder[nextDetectiveInd]:
-			curRole = DETECTIVE
-			player.SetRole(DETECTIVE)
-			if nextDetectiveInd < detectiveCnt+mafiaCnt-1 {
-				nextDetectiveInd++
-			}
-		default:
-			curRole = CIVILIAN
-			player.SetRole(CIVILIAN)
-			nextCivillianInd++
-		}
-		currentInd++
-		player.Notify(Notification{ROLE_ASSIGNED, curRole})
+	for id, player := range ms.players {
+		player.Notify(Notification{ROLE_ASSIGNED, ms.encodeRoleAssignment(id)})
 	}
+	ms.linkLovers()
+}
 
-	ms.mafiaAlive = mafiaCnt
-	ms.civilianAlive = playerCnt - mafiaCnt - detectiveCnt
+// eliminate kills the player and the lover who dies of a broken heart, the death notifications reveal
+// as much of the roles as the ruleset allows and are returned so that the caller decides when to announce them
+func (ms *mafiaSession) eliminate(id uint64, cause string) []Notification {
+	victim := ms.players[id]
+	if victim.GetRole() == JESTER && cause == DEATH_EXECUTION {
+		// the jester wanted exactly this
+		ms.extraWinners = append(ms.extraWinners, JESTER_FACTION)
+		ms.NotifyPlayers(Notification{JESTER_WON, victim.GetName()}, ALL)
+	}
+
+	ms.recordDeath(victim, cause)
+	info := victim.GetName() + " " + ms.announcedRole(victim.GetName())
+	if cause == DEATH_AFK || cause == DEATH_KICK {
+		info += " " + cause
+	}
+	notifications := []Notification{{PLAYER_ELIMINATED, info}}
+	victim.SetRole(GHOST)
+	if partnerId, ok := ms.partnerOf(id); ok && ms.players[partnerId].GetRole() != GHOST {
+		partner := ms.players[partnerId]
+		ms.recordDeath(partner, DEATH_HEARTBREAK)
+		notifications = append(notifications, Notification{PLAYER_ELIMINATED, partner.GetName() + " " + ms.revealedRole(partner.GetRole()) + " heartbreak"})
+		partner.SetRole(GHOST)
+	}
+	return notifications
 }
 
 func (ms *mafiaSession) endGameConditionReached() bool {
-	return ms.mafiaAlive == 0 || ms.mafiaAlive == ms.civilianAlive
+	_, ended := ms.winners()
+	return ended
 }
 
 func (ms *mafiaSession) carryOutExecution() {
 	ms.debug("carryOutExecution")
 	//ms.snapshot()
 	if ms.phase == DAY {
-		var (
-			maxVotes   = 0
-			collisions = 0
-			target     = ""
-		)
-
-		ms.debug("VICTIMS")
-		for victim, votes := range ms.potentialVictims {
-			fmt.Println(victim+": ", votes)
-			if votes > maxVotes {
-				maxVotes = votes
-				collisions = 0
-				target = victim
-			} else if votes == maxVotes {
-				collisions++
-			}
+		if len(ms.dayVotes) > 0 || len(ms.abstentions) > 0 {
+			ms.NotifyPlayers(Notification{VOTE_RESULTS, ms.encodeVoteResults()}, ALL)
 		}
 
-		fmt.Println("Collisions", collisions)
-		fmt.Println("target", target)
-		if collisions == 0 && target != "" {
-			confirmedVictimId, err := ms.getPlayersIdByName(target)
-			if err != nil {
-				ms.debug("DAY VICTIM ERROR")
-				//ms.snapshot()
-				log.Println(err.Error())
-				ms.NotifyPlayers(Notification{PLAYER_NOT_FOUND, target}, ALL)
-				ms.potentialVictims = make(map[string]int)
-				return
+		ms.lock.Lock()
+		ms.recordBallots(BALLOT_VOTE)
+		ms.lock.Unlock()
+		leaders := ms.leaders()
+		if len(leaders) > 1 && ms.rules.TieRule == TIE_REVOTE {
+			ms.revote(leaders)
+			ms.lock.Lock()
+			ms.recordBallots(BALLOT_REVOTE)
+			ms.lock.Unlock()
+			if len(ms.dayVotes) > 0 || len(ms.abstentions) > 0 {
+				ms.NotifyPlayers(Notification{VOTE_RESULTS, ms.encodeVoteResults()}, ALL)
 			}
-			if ms.players[confirmedVictimId].GetRole() == MAFIA {
-				ms.mafiaAlive--
-			} else if ms.players[confirmedVictimId].GetRole() == CIVILIAN {
-				ms.civilianAlive--
-			}
-			ms.NotifyPlayers(Notification{PLAYER_ELIMINATED, ms.players[confirmedVictimId].GetName() + " " + ms.players[confirmedVictimId].GetRole()}, ALL)
-			ms.players[confirmedVictimId].SetRole(GHOST)
+			leaders = ms.leaders()
+		}
+
+		ms.debug(fmt.Sprintf("LEADERS %v", leaders))
+		if len(leaders) == 0 {
+			ms.NotifyPlayers(Notification{eventType: NO_VOTES}, ALL)
+		} else if len(leaders) == 1 {
+			ms.executeDayVictims(leaders)
+		} else if ms.rules.TieRule == TIE_RANDOM {
+			victim := leaders[rand.Intn(len(leaders))]
+			ms.NotifyPlayers(Notification{TIE_BROKEN, victim}, ALL)
+			ms.executeDayVictims([]string{victim})
+		} else if ms.rules.TieRule == TIE_EXECUTE_ALL {
+			ms.executeDayVictims(leaders)
 		} else {
 			ms.NotifyPlayers(Notification{eventType: VOTES_MISMATCH}, ALL)
 		}
-		ms.potentialVictims = make(map[string]int)
-	} else {
-		if len(ms.potentialVictims) != 1 {
-			ms.NotifyPlayers(Notification{eventType: MAFIA_VOTES_MISMATCH}, MAFIA)
-		}
 
-		for victim := range ms.potentialVictims {
-			confirmedVictimId, err := ms.getPlayersIdByName(victim)
-			if err != nil {
-				ms.debug("NIGHT VICTIM ERROR")
-				//ms.snapshot()
-				log.Println(err.Error())
-				ms.NotifyPlayers(Notification{PLAYER_NOT_FOUND, victim}, MAFIA)
-				break
-			}
-			if ms.players[confirmedVictimId].GetRole() == MAFIA {
-				ms.mafiaAlive--
-			} else if ms.players[confirmedVictimId].GetRole() == CIVILIAN {
-				ms.civilianAlive--
-			}
-			// Notification will be shown only at the beginning of the Next Day
-			ms.delayedNotifications = append(ms.delayedNotifications, Notification{PLAYER_ELIMINATED, ms.players[confirmedVictimId].GetName() + " " + ms.players[confirmedVictimId].GetRole()})
-			ms.players[confirmedVictimId].SetRole(GHOST)
-		}
-		ms.potentialVictims = make(map[string]int)
+		ms.lock.Lock()
+		ms.revoteCandidates = nil
+		ms.lock.Unlock()
 	}
 	ms.debug("SUCCESS")
 	//ms.snapshot()
 
 }
 
+// waitForDayEnd blocks until every living player has skipped the day, the votes themselves are kept in dayVotes
+func (ms *mafiaSession) waitForDayEnd() {
+	ms.debug("WAIT ON SKIP")
+	// the players may leave meanwhile
+	ms.lock.Lock()
+	for _, player := range ms.players {
+		if player.GetRole() == GHOST {
+			continue
+		}
+		ms.waitGr.Add(1)
+
+		go func(player MafiaPlayer, wGroup *sync.WaitGroup) {
+			for _, dayEnded := player.WaitEndDay(); !dayEnded; _, dayEnded = player.WaitEndDay() {
+			}
+			wGroup.Done()
+		}(player, &ms.waitGr)
+	}
+	ms.lock.Unlock()
+	stop := make(chan struct{})
+	go ms.watchIdlePlayers(stop)
+	ms.waitGr.Wait()
+	close(stop)
+}
+
+// revote repeats the day vote among the tied candidates
+func (ms *mafiaSession) revote(candidates []string) {
+	ms.lock.Lock()
+	ms.dayVotes = make(map[uint64]string)
+	ms.abstentions = make(map[uint64]bool)
+	ms.revoteCandidates = candidates
+	ms.lock.Unlock()
+
+	for _, player := range ms.players {
+		player.SetActive(true)
+	}
+	ms.NotifyPlayers(Notification{REVOTE_START, strings.Join(candidates, "@@")}, ALL)
+	ms.waitForDayEnd()
+}
+
+// executeDayVictims eliminates the players chosen by the day vote
+func (ms *mafiaSession) executeDayVictims(victims []string) {
+	for _, victim := range victims {
+		victimId, err := ms.getPlayersIdByName(victim)
+		if ms.isDead(victim) {
+			// removed from the game after the votes had been counted
+			continue
+		}
+		if err != nil {
+			ms.debug("DAY VICTIM ERROR")
+			log.Println(err.Error())
+			ms.NotifyPlayers(Notification{PLAYER_NOT_FOUND, victim}, ALL)
+			continue
+		}
+		for _, notification := range ms.eliminate(victimId, DEATH_EXECUTION) {
+			ms.NotifyPlayers(notification, ALL)
+		}
+	}
+}
+
 func (ms *mafiaSession) runRound() {
 	ms.debug("runRound")
 	//ms.snapshot()
@@ -400,88 +749,34 @@ func (ms *mafiaSession) runRound() {
 	}
 
 	if ms.phase == DAY {
+		ms.lock.Lock()
+		ms.dayVotes = make(map[uint64]string)
+		ms.abstentions = make(map[uint64]bool)
+		ms.kickVotes = make(map[string]map[uint64]bool)
+		ms.nominees = nil
+		ms.lock.Unlock()
 		ms.NotifyPlayers(Notification{eventType: PHASE_START_DAY}, ALL)
 
 		time.Sleep(NOTIFICATION_DELAY)
 		ms.deliverDelayedNotifications()
 
-		ms.debug("WAIT ON SKIP")
-		for _, player := range ms.players {
-			if player.GetRole() == GHOST {
-				continue
-			}
-			ms.waitGr.Add(1)
-
-			// only the last vote will be counted
-			go func(player MafiaPlayer, mutex *sync.Mutex, wGroup *sync.WaitGroup) {
-				var lastVote string
-
-				for voteRes, dayEnded := player.WaitEndDay(); !dayEnded; voteRes, dayEnded = player.WaitEndDay() {
-					if voteRes != "" {
-						lastVote = voteRes
-					}
-				}
-
-				mutex.Lock()
-				if _, isAlreadyAVictim := ms.potentialVictims[lastVote]; isAlreadyAVictim {
-					ms.potentialVictims[lastVote] += 1
-				} else {
-					ms.potentialVictims[lastVote] = 0
-				}
-				mutex.Unlock()
-				wGroup.Done()
-			}(player, &ms.lock, &ms.waitGr)
+		ms.waitForDayEnd()
+		if ms.endGameConditionReached() {
+			// a player removed during the day has decided the game
+			return
+		}
+		if ms.rules.DayProcedure == TRIAL_DAY {
+			ms.runTrials()
+		} else {
+			ms.carryOutExecution()
+		}
+		for _, notification := range ms.removeIdlePlayers() {
+			ms.NotifyPlayers(notification, ALL)
 		}
-		ms.waitGr.Wait()
-		ms.carryOutExecution()
 		ms.phase = NIGHT
 	} else {
-		ms.NotifyPlayers(Notification{eventType: PHASE_START_NIGHT}, ALL)
-		ms.debug("WAITING ON NIGHT VOTES")
-		for _, player := range ms.players {
-			if player.GetRole() != MAFIA && player.GetRole() != DETECTIVE {
-				ms.debug(fmt.Sprintf("Player %s skipped", player.GetName()))
-				continue
-			}
-
-			ms.waitGr.Add(1)
-			go func(player MafiaPlayer, mutex *sync.Mutex, wGroup *sync.WaitGroup) {
-				voteRes := player.WaitForVote()
-				for ; !ms.nameTaken(voteRes); voteRes = player.WaitForVote() {
-					player.Notify(Notification{PLAYER_NOT_FOUND, voteRes})
-				}
-				ms.debug(fmt.Sprintf("Player %s voted for %s", player.GetName(), voteRes))
-				player.SetActive(false)
-				if player.GetRole() == DETECTIVE {
-					suspectId, err := ms.getPlayersIdByName(voteRes)
-					if err != nil {
-						log.Println(err.Error())
-						player.Notify(Notification{PLAYER_NOT_FOUND, voteRes})
-						wGroup.Done()
-						return
-					}
-
-					if suspect := ms.players[suspectId]; suspect.GetRole() == MAFIA {
-						player.SetExposed(suspect.GetName())
-						player.Notify(Notification{eventType: GUESS_SUCCESS})
-					} else {
-						player.SetExposed("")
-						player.Notify(Notification{eventType: GUESS_FAIL})
-					}
-				} else {
-					mutex.Lock()
-					if _, isAlreadyAVictim := ms.potentialVictims[voteRes]; isAlreadyAVictim {
-						ms.potentialVictims[voteRes] += 1
-					} else {
-						ms.potentialVictims[voteRes] = 0
-					}
-					mutex.Unlock()
-				}
-				wGroup.Done()
-			}(player, &ms.lock, &ms.waitGr)
-		}
-		ms.waitGr.Wait()
-		ms.carryOutExecution()
+		ms.runNight()
+		ms.delayedNotifications = append(ms.delayedNotifications, ms.removeIdlePlayers()...)
 		ms.roundCnt++
 		ms.phase = DAY
 	}
@@ -489,16 +784,33 @@ func (ms *mafiaSession) runRound() {
 
 func (ms *mafiaSession) Start() {
 	if len(ms.players) < PLAYERS_LOWER_LIM {
+		ms.status = WAITING
 		ms.NotifyPlayers(Notification{eventType: SESSION_ABORT}, ALL)
 		return
 	}
 
+	ms.resetPlayers()
 	ms.inProcess = true
+	ms.status = IN_PROGRESS
+	ms.chatHistory = nil
+	ms.graveyard = make(map[string]string)
+	ms.extraWinners = nil
+	ms.lovers = nil
+	ms.deaths = nil
+	ms.checks = nil
+	ms.reveals = nil
+	ms.ballots = nil
+	ms.nightActions = nil
+	ms.gameCnt++
+	ms.startedAt = time.Now()
+	ms.gameId = fmt.Sprintf("%s-%d", ms.startedAt.Format("20060102-150405"), ms.gameCnt)
+	ms.dayVotes = make(map[uint64]string)
+	ms.abstentions = make(map[uint64]bool)
 	ms.roundCnt = 0
 	ms.phase = DAY
 	ms.shuffleRoles()
 	log.Println("GAME SESSION STARTED")
-	ms.NotifyPlayers(Notification{eventType: SESSION_START}, ALL)
+	ms.NotifyPlayers(Notification{SESSION_START, ms.composition.encode()}, ALL)
 	for !ms.endGameConditionReached() {
 		ms.runRound()
 	}
@@ -508,18 +820,19 @@ func (ms *mafiaSession) Start() {
 
 func (ms *mafiaSession) end() {
 	ms.inProcess = false
+	ms.status = ENDED
 	log.Println("GAME SESSION ENDED")
-	if ms.mafiaAlive == 0 {
-		ms.NotifyPlayers(Notification{SESSION_END, "civilians have won"}, ALL)
-	} else {
-		ms.NotifyPlayers(Notification{SESSION_END, "mafia has won"}, ALL)
-	}
+	winners, _ := ms.winners()
+	ms.NotifyPlayers(Notification{GAME_SUMMARY, ms.encodeSummary()}, ALL)
+	ms.storeReport(ms.buildReport(winners))
+	ms.NotifyPlayers(Notification{GAME_REPORT, ms.gameId}, ALL)
+	ms.NotifyPlayers(Notification{SESSION_END, strings.Join(winners, "@@")}, ALL)
+	ms.openLobby()
 
 	//for _, player := range ms.players {
 	//	player.CancelNotifications()
 	//}
 	//
 	//ms.players = make(map[uint64]MafiaPlayer)
-	ms.potentialVictims = make(map[string]int)
 	ms.delayedNotifications = ms.delayedNotifications[:0]
 }
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tiedDay makes a day where p2 and p3 have two votes each
+func tiedDay(tieRule string) *mafiaSession {
+	rules := DefaultRuleset
+	rules.TieRule = tieRule
+	ms := newTestGame(rules, DAY, MAFIA, MAFIA, CIVILIAN, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.dayVotes = map[uint64]string{0: "p2", 1: "p2", 4: "p3", 5: "p3"}
+	return ms
+}
+
+func TestTieRules(t *testing.T) {
+	for rule, deaths := range map[string]int{TIE_NO_EXECUTION: 0, TIE_RANDOM: 1, TIE_EXECUTE_ALL: 2} {
+		ms := tiedDay(rule)
+		ms.carryOutExecution()
+		if len(ms.graveyard) != deaths {
+			t.Errorf("%s: %d players executed instead of %d", rule, len(ms.graveyard), deaths)
+		}
+		for victim := range ms.graveyard {
+			if victim != "p2" && victim != "p3" {
+				t.Errorf("%s: %s has been executed without being tied", rule, victim)
+			}
+		}
+	}
+}
+
+func TestRevote(t *testing.T) {
+	ms := tiedDay(TIE_REVOTE)
+	events, err := ms.SubscribeToPlayersNotifications(2, 0)
+	if err != nil {
+		t.Fatalf("couldn't subscribe: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		ms.carryOutExecution()
+		close(done)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for record, err := events.pop(ctx); record.event.eventType != REVOTE_START; record, err = events.pop(ctx) {
+		if err != nil {
+			t.Fatalf("the revote hasn't started: %v", err)
+		}
+	}
+
+	ms.PlayerVote(0, "p1")
+	if _, voted := ms.dayVotes[0]; voted {
+		t.Errorf("a vote for a player who isn't on the revote has been counted")
+	}
+	for id := range ms.players {
+		if id != 3 {
+			ms.PlayerVote(id, "p3")
+		}
+		ms.PlayerEndDay(id)
+	}
+	<-done
+
+	if _, executed := ms.graveyard["p3"]; len(ms.graveyard) != 1 || !executed {
+		t.Errorf("only p3 should have been executed after the revote, got %v", ms.graveyard)
+	}
+}
+
+func TestAbstain(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.PlayerVote(1, "p0")
+	ms.PlayerAbstain(1)
+	if _, voted := ms.dayVotes[1]; voted || !ms.abstentions[1] {
+		t.Errorf("abstaining should withdraw the vote, got %v and %v", ms.dayVotes, ms.abstentions)
+	}
+	if !ms.players[1].IsActive() {
+		t.Errorf("abstaining shouldn't end the player's day")
+	}
+}
+
+func TestChatChannels(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE, GHOST)
+	for _, msg := range []struct {
+		id        uint64
+		channel   string
+		recipient string
+	}{
+		{0, "", ""},
+		{0, MAFIA_CHANNEL, ""},
+		{2, DIRECT_CHANNEL, "p3"},
+		{5, "", ""},
+	} {
+		if err := ms.SendChatMsg(msg.id, msg.channel, msg.recipient, "hi"); err != nil {
+			t.Fatalf("p%d couldn't write to %q: %v", msg.id, msg.channel, err)
+		}
+	}
+	if err := ms.SendChatMsg(2, DIRECT_CHANNEL, "p5", "hi"); err != directMessageGhostError {
+		t.Errorf("a message to a ghost should fail with %v, got %v", directMessageGhostError, err)
+	}
+
+	for _, test := range []struct {
+		id       uint64
+		channel  string
+		channels []string
+	}{
+		{1, "", []string{PUBLIC_CHANNEL, MAFIA_CHANNEL}},
+		{3, "", []string{PUBLIC_CHANNEL, DIRECT_CHANNEL}},
+		{3, DIRECT_CHANNEL, []string{DIRECT_CHANNEL}},
+		{4, "", []string{PUBLIC_CHANNEL}},
+		{5, "", []string{PUBLIC_CHANNEL, GHOSTS_CHANNEL}},
+	} {
+		history, err := ms.GetChatHistory(test.id, test.channel)
+		var channels []string
+		for _, message := range history {
+			channels = append(channels, message.channel)
+		}
+		if err != nil || strings.Join(channels, ",") != strings.Join(test.channels, ",") {
+			t.Errorf("p%d reads %v of %q instead of %v, %v", test.id, channels, test.channel, test.channels, err)
+		}
+	}
+}
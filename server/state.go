@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PlayerState is what everyone can see about a player
+type PlayerState struct {
+	Name  string
+	Alive bool
+	// Role is revealed only after the player has been eliminated
+	Role  string
+	Ready bool
+}
+
+// VoteCount is the number of public day votes cast against a player
+type VoteCount struct {
+	Target string
+	Votes  int
+	// Voters are known only when voting is open
+	Voters []string
+}
+
+// GameState is a snapshot of the session as it is seen by one of the players
+type GameState struct {
+	Status   string
+	Phase    string
+	Round    int
+	TimeLeft time.Duration
+	Role     string
+	Voted    bool
+	Skipped  bool
+	Stage    string
+	Accused  string
+	Host     string
+	Room     string
+	Prefer   string
+	Avoid    string
+	Players  []PlayerState
+	Tally    []VoteCount
+	// Abstainers are shown along with the tally
+	Abstainers []string
+}
+
+// tally counts the current day votes, the most voted players go first
+func (ms *mafiaSession) tally() []VoteCount {
+	voters := make(map[string][]string)
+	for id, target := range ms.dayVotes {
+		if player, ok := ms.players[id]; ok {
+			voters[target] = append(voters[target], player.GetName())
+		}
+	}
+
+	res := make([]VoteCount, 0, len(voters))
+	for target, names := range voters {
+		sort.Strings(names)
+		count := VoteCount{Target: target, Votes: len(names)}
+		if ms.rules.OpenVoting {
+			count.Voters = names
+		}
+		res = append(res, count)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Votes != res[j].Votes {
+			return res[i].Votes > res[j].Votes
+		}
+		return res[i].Target < res[j].Target
+	})
+
+	return res
+}
+
+// GetGameState describes the session for the player: status, phase, everyone's fate and the player's own progress in the current phase
+func (ms *mafiaSession) GetGameState(id uint64) (GameState, error) {
+	player, ok := ms.players[id]
+	if !ok {
+		return GameState{}, playerRemovedError
+	}
+
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	state := GameState{
+		Status: ms.status,
+		Role:   player.GetRole(),
+		Room:   ms.room.Code,
+	}
+	pref := ms.roleRecords.preference(player.GetName())
+	state.Prefer, state.Avoid = pref.Prefer, pref.Avoid
+	if (ms.status == COUNTDOWN || ms.status == IN_PROGRESS) && time.Now().Before(ms.deadline) {
+		state.TimeLeft = time.Until(ms.deadline)
+	}
+
+	if ms.status == IN_PROGRESS {
+		state.Phase = phaseName(ms.phase)
+		state.Round = ms.roundCnt + 1
+		if ms.phase == DAY && ms.rules.DayProcedure == TRIAL_DAY {
+			state.Stage = stageName(ms.dayStage)
+			state.Accused = ms.accused
+		}
+		if ms.phase == DAY && ms.dayStage == VERDICT_STAGE {
+			_, state.Voted = ms.verdicts[id]
+		} else if ms.phase == DAY {
+			_, state.Voted = ms.dayVotes[id]
+			state.Skipped = !player.IsActive() && player.GetRole() != GHOST
+			if ms.rules.OpenVoting {
+				state.Tally = ms.tally()
+				state.Abstainers = ms.abstainers()
+			}
+		} else {
+			_, proposed := ms.mafiaVotes[id]
+			_, targeted := ms.maniacTargets[id]
+			state.Voted = proposed || targeted || (player.GetRole() == DETECTIVE && !player.IsActive())
+		}
+	} else if ms.status == ENDED {
+		state.Round = ms.roundCnt + 1
+	}
+
+	if host, ok := ms.players[ms.host]; ok && ms.hasHost {
+		state.Host = host.GetName()
+	}
+	for pId, p := range ms.players {
+		role, dead := ms.graveyard[p.GetName()]
+		if dead && ms.status != ENDED {
+			role = ms.announcedRole(p.GetName())
+		}
+		state.Players = append(state.Players, PlayerState{Name: p.GetName(), Alive: !dead, Role: role, Ready: ms.ready[pId]})
+	}
+	sort.Slice(state.Players, func(i, j int) bool {
+		return state.Players[i].Name < state.Players[j].Name
+	})
+
+	return state, nil
+}
+
+// abstainers lists the players who explicitly abstained from the current day vote
+func (ms *mafiaSession) abstainers() []string {
+	var names []string
+	for id := range ms.abstentions {
+		if player, ok := ms.players[id]; ok {
+			names = append(names, player.GetName())
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// leaders are the players who got the most day votes, more than one of them means a tie
+func (ms *mafiaSession) leaders() []string {
+	var names []string
+	tally := ms.tally()
+	for _, count := range tally {
+		if count.Votes < tally[0].Votes {
+			break
+		}
+		names = append(names, count.Target)
+	}
+
+	return names
+}
+
+// GetVoteTally returns the running count of the current day votes and who abstained, it is public only when voting is open
+func (ms *mafiaSession) GetVoteTally(id uint64) ([]VoteCount, []string, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if _, ok := ms.players[id]; !ok {
+		return nil, nil, playerRemovedError
+	}
+	if !ms.rules.OpenVoting {
+		return nil, nil, closedVotingError
+	}
+	if !ms.inProcess || ms.phase != DAY {
+		return nil, nil, nil
+	}
+	return ms.tally(), ms.abstainers(), nil
+}
+
+// encodeVoteResults packs the votes of the day into a notification: a line per candidate with the number of votes and the voters
+// (only if voting is open), abstentions go last with an empty candidate
+func (ms *mafiaSession) encodeVoteResults() string {
+	var lines []string
+	for _, count := range ms.tally() {
+		lines = append(lines, fmt.Sprintf("%s@@%d@@%s", count.Target, count.Votes, strings.Join(count.Voters, ",")))
+	}
+	if abstainers := ms.abstainers(); len(abstainers) > 0 {
+		if !ms.rules.OpenVoting {
+			abstainers = nil
+		}
+		lines = append(lines, fmt.Sprintf("@@%d@@%s", len(ms.abstentions), strings.Join(abstainers, ",")))
+	}
+
+	return strings.Join(lines, "\n")
+}
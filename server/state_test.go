@@ -0,0 +1,54 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestClosedVotingHidesTally(t *testing.T) {
+	rules := rulesetPresets(DefaultRuleset)[RULESET_TRIAL]
+	ms := newTestGame(rules, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.dayVotes = map[uint64]string{0: "p1", 2: "p1"}
+	ms.abstentions = map[uint64]bool{3: true}
+
+	if _, _, err := ms.GetVoteTally(1); err != closedVotingError {
+		t.Errorf("the tally of a secret vote should fail with %v, got %v", closedVotingError, err)
+	}
+	if results := ms.encodeVoteResults(); results != "p1@@2@@\n@@1@@" {
+		t.Errorf("the results of a secret vote name the voters: %q", results)
+	}
+
+	ms.rules.OpenVoting = true
+	if results := ms.encodeVoteResults(); results != "p1@@2@@p0,p2\n@@1@@p3" {
+		t.Errorf("the results of an open vote are %q", results)
+	}
+}
+
+func TestGameState(t *testing.T) {
+	ms := newTestGame(DefaultRuleset, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.eliminate(3, DEATH_EXECUTION)
+	ms.dayVotes = map[uint64]string{0: "p2", 2: "p0"}
+	ms.PlayerEndDay(1)
+
+	state, err := ms.GetGameState(0)
+	if err != nil {
+		t.Fatalf("couldn't get the state: %v", err)
+	}
+	if state.Status != IN_PROGRESS || state.Phase != phaseName(DAY) || state.Round != 2 || state.Role != MAFIA || !state.Voted || state.Skipped {
+		t.Errorf("unexpected state of the mafia: %+v", state)
+	}
+	if len(state.Tally) != 2 || len(state.Players) != 4 {
+		t.Errorf("the open tally or the players are wrong: %+v", state)
+	}
+	for _, player := range state.Players {
+		if dead := player.Name == "p3"; player.Alive == dead || (dead && player.Role != DETECTIVE) || (!dead && player.Role != "") {
+			t.Errorf("unexpected state of %s: %+v", player.Name, player)
+		}
+	}
+
+	if state, _ := ms.GetGameState(1); !state.Skipped || state.Voted {
+		t.Errorf("the civilian has skipped the day without voting, got %+v", state)
+	}
+	if _, err := ms.GetGameState(7); err != playerRemovedError {
+		t.Errorf("the state of an unknown player should fail with %v, got %v", playerRemovedError, err)
+	}
+}
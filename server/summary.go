@@ -0,0 +1,184 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// deathRecord is a single death of the game for the end of the game summary
+type deathRecord struct {
+	round  int
+	phase  string
+	victim string
+	role   string
+	cause  string
+}
+
+// checkRecord is a single night check made by a detective or the Don
+type checkRecord struct {
+	round   int
+	checker string
+	role    string
+	target  string
+	found   bool
+}
+
+// recordDeath remembers the victim's role and how they died, a player dies only once
+func (ms *mafiaSession) recordDeath(victim MafiaPlayer, cause string) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if _, dead := ms.graveyard[victim.GetName()]; dead {
+		return
+	}
+
+	ms.graveyard[victim.GetName()] = victim.GetRole()
+	ms.deaths = append(ms.deaths, deathRecord{ms.roundCnt + 1, phaseName(ms.phase), victim.GetName(), victim.GetRole(), cause})
+}
+
+// isDead tells whether the player has died or has been removed from the current game
+func (ms *mafiaSession) isDead(name string) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	_, dead := ms.graveyard[name]
+	return dead
+}
+
+// revealedRole is the part of the role announced on death according to the ruleset
+func (ms *mafiaSession) revealedRole(role string) string {
+	switch ms.rules.DeathReveal {
+	case REVEAL_TEAM:
+		if isMafia(role) {
+			return MAFIA_FACTION
+		} else if factionOf(role) != TOWN_FACTION {
+			return "neutral"
+		}
+		return "town"
+	case REVEAL_NONE:
+		return "hidden"
+	}
+
+	return role
+}
+
+// announcedRole is the part of the dead player's role the others know, a removed player's one may stay secret
+func (ms *mafiaSession) announcedRole(name string) string {
+	for _, death := range ms.deaths {
+		if death.victim == name && (death.cause == DEATH_AFK || death.cause == DEATH_KICK) && !ms.rules.RevealRemoved {
+			return "hidden"
+		}
+	}
+
+	return ms.revealedRole(ms.graveyard[name])
+}
+
+// describeRole renders the revealed role of a dead player
+func describeRole(role string) string {
+	switch role {
+	case "hidden":
+		return "kept the role secret"
+	case "town":
+		return "played for the town"
+	case "neutral":
+		return "played for no side"
+	}
+
+	return "was a " + role
+}
+
+// encodeSummary lists every player's role, every death and every night check of the game,
+// one entry per line with the fields separated by @@
+func (ms *mafiaSession) encodeSummary() string {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	var lines, roles []string
+	for _, player := range ms.players {
+		role, dead := ms.graveyard[player.GetName()]
+		if !dead {
+			role = player.GetRole()
+		}
+		roles = append(roles, fmt.Sprintf("role@@%s@@%s@@%t", player.GetName(), role, !dead))
+	}
+	sort.Strings(roles)
+	lines = append(lines, roles...)
+	if len(ms.lovers) == 2 {
+		lines = append(lines, fmt.Sprintf("lovers@@%s@@%s", ms.players[ms.lovers[0]].GetName(), ms.players[ms.lovers[1]].GetName()))
+	}
+	for _, death := range ms.deaths {
+		lines = append(lines, fmt.Sprintf("death@@%s@@%d@@%s@@%s@@%s", death.phase, death.round, death.victim, death.role, death.cause))
+	}
+	for _, check := range ms.checks {
+		lines = append(lines, fmt.Sprintf("check@@%d@@%s@@%s@@%s@@%t", check.round, check.checker, check.role, check.target, check.found))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatSummary renders the GAME_SUMMARY info
+func formatSummary(info string) string {
+	roles := []string{"Roles:"}
+	deaths := []string{"Deaths:"}
+	checks := []string{"Night checks:"}
+	for _, line := range strings.Split(info, "\n") {
+		entry := strings.Split(line, "@@")
+		switch entry[0] {
+		case "role":
+			if len(entry) == 4 {
+				status := "alive"
+				if alive, _ := strconv.ParseBool(entry[3]); !alive {
+					status = "dead"
+				}
+				roles = append(roles, fmt.Sprintf("  %s: %s (%s)", entry[1], entry[2], status))
+			}
+		case "lovers":
+			if len(entry) == 3 {
+				roles = append(roles, fmt.Sprintf("  %s and %s were lovers", entry[1], entry[2]))
+			}
+		case "death":
+			if len(entry) == 6 {
+				deaths = append(deaths, fmt.Sprintf("  %s %s: %s (%s) %s", strings.ToUpper(entry[1][:1])+entry[1][1:], entry[2], entry[3], entry[4], describeDeath(entry[5])))
+			}
+		case "check":
+			if len(entry) == 6 {
+				result := "not found"
+				if found, _ := strconv.ParseBool(entry[5]); found {
+					result = "found"
+				}
+				looking := "the mafia"
+				if entry[3] == DON {
+					looking = "the detective"
+				}
+				checks = append(checks, fmt.Sprintf("  Night %s: %s %s checked %s looking for %s - %s", entry[1], entry[3], entry[2], entry[4], looking, result))
+			}
+		}
+	}
+
+	lines := append([]string{"---- GAME SUMMARY ----"}, roles...)
+	if len(deaths) > 1 {
+		lines = append(lines, deaths...)
+	}
+	if len(checks) > 1 {
+		lines = append(lines, checks...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeDeath(cause string) string {
+	switch cause {
+	case DEATH_EXECUTION:
+		return "was executed by the town"
+	case DEATH_MAFIA:
+		return "was killed by the mafia"
+	case DEATH_MANIAC:
+		return "was killed by the maniac"
+	case DEATH_HEARTBREAK:
+		return "died of a broken heart"
+	case DEATH_AFK:
+		return "was removed for idling"
+	case DEATH_KICK:
+		return "was kicked by a vote"
+	}
+
+	return "died"
+}
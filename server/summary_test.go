@@ -0,0 +1,45 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeathReveal(t *testing.T) {
+	for mode, announced := range map[string]string{REVEAL_FULL: DETECTIVE, REVEAL_TEAM: "town", REVEAL_NONE: "hidden"} {
+		rules := DefaultRuleset
+		rules.DeathReveal = mode
+		ms := newTestGame(rules, DAY, MAFIA, CIVILIAN, CIVILIAN, DETECTIVE)
+
+		notifications := ms.eliminate(3, DEATH_EXECUTION)
+		if len(notifications) != 1 || notifications[0].info != "p3 "+announced {
+			t.Errorf("%s: the death is announced as %v instead of %q", mode, notifications, "p3 "+announced)
+		}
+		state, _ := ms.GetGameState(0)
+		if dead := state.Players[3]; dead.Alive || dead.Role != announced {
+			t.Errorf("%s: the dead detective is seen as %+v", mode, dead)
+		}
+
+		// the summary at the end of the game tells everything
+		summary := ms.encodeSummary()
+		if !strings.Contains(summary, "role@@p3@@detective@@false") || !strings.Contains(summary, "death@@day@@2@@p3@@detective@@execution") {
+			t.Errorf("%s: the summary hides the roles:\n%s", mode, summary)
+		}
+	}
+}
+
+func TestFormatSummary(t *testing.T) {
+	info := "role@@p0@@mafia@@true\nrole@@p1@@detective@@false\nlovers@@p0@@p1\ndeath@@night@@1@@p1@@detective@@mafia\ncheck@@1@@p1@@detective@@p0@@true"
+	expected := `---- GAME SUMMARY ----
+Roles:
+  p0: mafia (alive)
+  p1: detective (dead)
+  p0 and p1 were lovers
+Deaths:
+  Night 1: p1 (detective) ` + describeDeath(DEATH_MAFIA) + `
+Night checks:
+  Night 1: detective p1 checked p0 looking for the mafia - found`
+	if summary := formatSummary(info); summary != expected {
+		t.Errorf("the summary is rendered as\n%s\ninstead of\n%s", summary, expected)
+	}
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// passTargetConditions checks that the day vote or nomination targets a living player
+func (ms *mafiaSession) passTargetConditions(id uint64, target string) bool {
+	if _, err := ms.getPlayersIdByName(target); err != nil {
+		ms.players[id].Notify(Notification{PLAYER_NOT_FOUND, target})
+	} else if _, dead := ms.graveyard[target]; dead {
+		ms.players[id].Notify(Notification{VOTING_RESTRICTED, fmt.Sprintf("%s has already been eliminated", target)})
+	} else {
+		return true
+	}
+
+	return false
+}
+
+func (ms *mafiaSession) passNominateConditions(id uint64) bool {
+	player := ms.players[id]
+	_, nominated := ms.dayVotes[id]
+	if ms.rules.DayProcedure != TRIAL_DAY {
+		player.Notify(Notification{VOTING_RESTRICTED, "there are no nominations in this game, vote instead"})
+	} else if !ms.inProcess || ms.phase != DAY || ms.dayStage != NOMINATION_STAGE {
+		player.Notify(Notification{VOTING_RESTRICTED, "players may be nominated only at the beginning of the day"})
+	} else if ms.roundCnt == 0 {
+		player.Notify(Notification{VOTING_RESTRICTED, "you are not allowed to nominate on the first day"})
+	} else if player.GetRole() == GHOST {
+		player.Notify(Notification{VOTING_RESTRICTED, "you may only spectate as a ghost"})
+	} else if !player.IsActive() {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already skipped the current day"})
+	} else if nominated {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already nominated a player"})
+	} else {
+		return true
+	}
+
+	return false
+}
+
+// PlayerNominate puts the target on the list of the accused, every player may nominate once a day
+func (ms *mafiaSession) PlayerNominate(id uint64, target string) {
+	ms.touch(id)
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passNominateConditions(id) || !ms.passTargetConditions(id, target) {
+		return
+	}
+
+	ms.dayVotes[id] = target
+	isNew := true
+	for _, nominee := range ms.nominees {
+		if nominee == target {
+			isNew = false
+		}
+	}
+	if isNew {
+		ms.nominees = append(ms.nominees, target)
+	}
+	ms.NotifyPlayers(Notification{NOMINATION_MADE, ms.players[id].GetName() + "@@" + target}, ALL)
+}
+
+func (ms *mafiaSession) passVerdictConditions(id uint64) bool {
+	player := ms.players[id]
+	_, voted := ms.verdicts[id]
+	if !ms.inProcess || ms.phase != DAY || ms.dayStage != VERDICT_STAGE {
+		player.Notify(Notification{VOTING_RESTRICTED, "there is no verdict to vote on right now"})
+	} else if player.GetRole() == GHOST {
+		player.Notify(Notification{VOTING_RESTRICTED, "you may only spectate as a ghost"})
+	} else if player.GetName() == ms.accused {
+		player.Notify(Notification{VOTING_RESTRICTED, "you can't vote on your own verdict"})
+	} else if voted {
+		player.Notify(Notification{VOTING_RESTRICTED, "you have already voted"})
+	} else {
+		return true
+	}
+
+	return false
+}
+
+// PlayerVerdict records the player's guilty or innocent vote on the current accused
+func (ms *mafiaSession) PlayerVerdict(id uint64, guilty bool) {
+	ms.touch(id)
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if !ms.passVerdictConditions(id) {
+		return
+	}
+
+	ms.verdicts[id] = guilty
+	if ms.rules.OpenVoting {
+		ms.NotifyPlayers(Notification{VERDICT_CAST, ms.players[id].GetName() + "@@" + ms.accused + "@@" + verdictName(guilty)}, ALL)
+	}
+	if len(ms.verdicts) == ms.jurySize() {
+		select {
+		case ms.verdictDone <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// jurySize is the number of living players who vote on the verdict
+func (ms *mafiaSession) jurySize() int {
+	size := 0
+	for _, player := range ms.players {
+		if player.GetRole() != GHOST && player.GetName() != ms.accused {
+			size++
+		}
+	}
+
+	return size
+}
+
+func verdictName(guilty bool) string {
+	if guilty {
+		return "guilty"
+	}
+
+	return "innocent"
+}
+
+func (ms *mafiaSession) setDayStage(stage int, accused string, duration time.Duration) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	ms.dayStage = stage
+	ms.accused = accused
+	ms.deadline = time.Now().Add(duration)
+	if stage == VERDICT_STAGE {
+		ms.verdicts = make(map[uint64]bool)
+		ms.verdictDone = make(chan struct{}, 1)
+	}
+}
+
+// runTrials gives the nominees their last words and a verdict one after another, until someone is found guilty
+func (ms *mafiaSession) runTrials() {
+	ms.lock.Lock()
+	nominees := ms.nominees
+	ms.recordBallots(BALLOT_NOMINATION)
+	ms.lock.Unlock()
+	if len(nominees) == 0 {
+		ms.NotifyPlayers(Notification{eventType: NO_NOMINATIONS}, ALL)
+		return
+	}
+
+	for _, accused := range nominees {
+		if ms.endGameConditionReached() {
+			break
+		}
+		if ms.isDead(accused) {
+			// removed from the game since the nomination
+			continue
+		}
+		if _, err := ms.getPlayersIdByName(accused); err != nil {
+			ms.NotifyPlayers(Notification{PLAYER_NOT_FOUND, accused}, ALL)
+			continue
+		}
+
+		ms.setDayStage(DEFENCE_STAGE, accused, ms.rules.DefenceTime)
+		ms.NotifyPlayers(Notification{DEFENCE_START, accused + "@@" + strconv.Itoa(int(ms.rules.DefenceTime/time.Second))}, ALL)
+		time.Sleep(ms.rules.DefenceTime)
+
+		ms.setDayStage(VERDICT_STAGE, accused, ms.rules.VerdictTime)
+		ms.NotifyPlayers(Notification{VERDICT_START, accused + "@@" + strconv.Itoa(int(ms.rules.VerdictTime/time.Second))}, ALL)
+		select {
+		case <-ms.verdictDone:
+		case <-time.After(ms.rules.VerdictTime):
+		}
+
+		ms.lock.Lock()
+		guiltyCnt, innocentCnt := 0, 0
+		for id, guilty := range ms.verdicts {
+			if player, ok := ms.players[id]; ok {
+				ms.ballots = append(ms.ballots, ballotRecord{ms.roundCnt + 1, BALLOT_VERDICT, player.GetName(), accused, verdictName(guilty)})
+			}
+			if guilty {
+				guiltyCnt++
+			} else {
+				innocentCnt++
+			}
+		}
+		ms.dayStage = NOMINATION_STAGE
+		ms.lock.Unlock()
+
+		if ms.isDead(accused) {
+			// removed from the game during the trial, there is nothing to judge
+			continue
+		}
+		guilty := guiltyCnt > 0 && float64(guiltyCnt) > ms.rules.GuiltyThreshold*float64(guiltyCnt+innocentCnt)
+		ms.NotifyPlayers(Notification{VERDICT_RESULT, fmt.Sprintf("%s@@%s@@%d@@%d", accused, verdictName(guilty), guiltyCnt, innocentCnt)}, ALL)
+		if guilty {
+			if accusedId, err := ms.getPlayersIdByName(accused); err == nil {
+				for _, notification := range ms.eliminate(accusedId, DEATH_EXECUTION) {
+					ms.NotifyPlayers(notification, ALL)
+				}
+			}
+			break
+		}
+	}
+
+	ms.setDayStage(NOMINATION_STAGE, "", 0)
+}
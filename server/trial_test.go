@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// awaitVerdict waits for the verdict on the accused to be open for votes
+func awaitVerdict(t *testing.T, events *notificationQueue, accused string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for {
+		record, err := events.pop(ctx)
+		if err != nil {
+			t.Fatalf("the verdict on %s hasn't started: %v", accused, err)
+		}
+		if record.event.eventType == VERDICT_START && strings.HasPrefix(record.event.info, accused+"@@") {
+			return
+		}
+	}
+}
+
+func TestTrialVerdicts(t *testing.T) {
+	rules := DefaultRuleset
+	rules.DayProcedure, rules.DefenceTime, rules.VerdictTime = TRIAL_DAY, 0, time.Second
+	ms := newTestGame(rules, DAY, MAFIA, MAFIA, CIVILIAN, CIVILIAN, CIVILIAN, DETECTIVE)
+	ms.PlayerNominate(0, "p2")
+	ms.PlayerNominate(2, "p0")
+	ms.PlayerNominate(1, "p2")
+	if len(ms.nominees) != 2 {
+		t.Fatalf("a player nominated twice should be on trial once, got %v", ms.nominees)
+	}
+
+	events, err := ms.SubscribeToPlayersNotifications(5, 0)
+	if err != nil {
+		t.Fatalf("couldn't subscribe: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		ms.runTrials()
+		close(done)
+	}()
+
+	// two guilty votes out of five don't pass the threshold
+	awaitVerdict(t, events, "p2")
+	ms.PlayerVerdict(2, true)
+	if _, voted := ms.verdicts[2]; voted {
+		t.Errorf("the accused has voted on their own verdict")
+	}
+	for _, id := range []uint64{0, 1, 3, 4, 5} {
+		ms.PlayerVerdict(id, id < 2)
+	}
+
+	awaitVerdict(t, events, "p0")
+	for _, id := range []uint64{1, 2, 3, 4, 5} {
+		ms.PlayerVerdict(id, id != 1)
+	}
+	<-done
+
+	if len(ms.graveyard) != 1 || ms.players[0].GetRole() != GHOST {
+		t.Errorf("only p0 should have been found guilty, got %v", ms.graveyard)
+	}
+	if ms.dayStage != NOMINATION_STAGE || ms.accused != "" {
+		t.Errorf("the trials have left the day in stage %d with %q accused", ms.dayStage, ms.accused)
+	}
+}
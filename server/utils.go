@@ -1,6 +1,14 @@
 package server
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 // ---- notifications
 type notificationEvent uint16
@@ -26,16 +34,174 @@ const (
 	PHASE_START_NIGHT
 	CHAT_MSG
 	CHAT_RESTRICTED
+	PLAYER_MUTED
+	PLAYER_UNMUTED
+	NOTIFICATIONS_SKIPPED
+	VOTE_CAST
+	VOTE_RESULTS
+	NOMINATION_MADE
+	NO_NOMINATIONS
+	DEFENCE_START
+	VERDICT_START
+	VERDICT_CAST
+	VERDICT_RESULT
+	VOTE_ABSTAINED
+	NO_VOTES
+	REVOTE_START
+	TIE_BROKEN
+	MAFIA_PROPOSAL
+	MAFIA_DECISION
+	DON_CHECK_SUCCESS
+	DON_CHECK_FAIL
+	MANIAC_TARGET
+	JESTER_WON
+	LOVERS_LINKED
+	GAME_SUMMARY
+	GAME_REPORT
+	LOBBY_OPEN
+	PLAYER_READY
+	PLAYER_UNREADY
+	HOST_ASSIGNED
+	PLAYER_KICKED
+	COUNTDOWN_CANCELLED
+	AFK_WARNING
+	AFK_MISSED
+	AFK_SKIPPED
+	KICK_VOTE
+	PLAYER_CLEARED
+	CHECK_SHARED
 )
 
+var notificationEventNames = [...]string{
+	CLIENT_CONNECTED:      "CLIENT_CONNECTED",
+	CLIENT_DISCONNECTED:   "CLIENT_DISCONNECTED",
+	SESSION_DISCLAIMER:    "SESSION_DISCLAIMER",
+	SESSION_START:         "SESSION_START",
+	SESSION_ABORT:         "SESSION_ABORT",
+	SESSION_END:           "SESSION_END",
+	ROLE_ASSIGNED:         "ROLE_ASSIGNED",
+	PLAYER_NOT_FOUND:      "PLAYER_NOT_FOUND",
+	PLAYER_ELIMINATED:     "PLAYER_ELIMINATED",
+	PLAYER_EXPOSED:        "PLAYER_EXPOSED",
+	NO_EXPOSED_PLAYER:     "NO_EXPOSED_PLAYER",
+	GUESS_SUCCESS:         "GUESS_SUCCESS",
+	GUESS_FAIL:            "GUESS_FAIL",
+	VOTING_RESTRICTED:     "VOTING_RESTRICTED",
+	VOTES_MISMATCH:        "VOTES_MISMATCH",
+	MAFIA_VOTES_MISMATCH:  "MAFIA_VOTES_MISMATCH",
+	PHASE_START_DAY:       "PHASE_START_DAY",
+	PHASE_START_NIGHT:     "PHASE_START_NIGHT",
+	CHAT_MSG:              "CHAT_MSG",
+	CHAT_RESTRICTED:       "CHAT_RESTRICTED",
+	PLAYER_MUTED:          "PLAYER_MUTED",
+	PLAYER_UNMUTED:        "PLAYER_UNMUTED",
+	NOTIFICATIONS_SKIPPED: "NOTIFICATIONS_SKIPPED",
+	VOTE_CAST:             "VOTE_CAST",
+	VOTE_RESULTS:          "VOTE_RESULTS",
+	NOMINATION_MADE:       "NOMINATION_MADE",
+	NO_NOMINATIONS:        "NO_NOMINATIONS",
+	DEFENCE_START:         "DEFENCE_START",
+	VERDICT_START:         "VERDICT_START",
+	VERDICT_CAST:          "VERDICT_CAST",
+	VERDICT_RESULT:        "VERDICT_RESULT",
+	VOTE_ABSTAINED:        "VOTE_ABSTAINED",
+	NO_VOTES:              "NO_VOTES",
+	REVOTE_START:          "REVOTE_START",
+	TIE_BROKEN:            "TIE_BROKEN",
+	MAFIA_PROPOSAL:        "MAFIA_PROPOSAL",
+	MAFIA_DECISION:        "MAFIA_DECISION",
+	DON_CHECK_SUCCESS:     "DON_CHECK_SUCCESS",
+	DON_CHECK_FAIL:        "DON_CHECK_FAIL",
+	MANIAC_TARGET:         "MANIAC_TARGET",
+	JESTER_WON:            "JESTER_WON",
+	LOVERS_LINKED:         "LOVERS_LINKED",
+	GAME_SUMMARY:          "GAME_SUMMARY",
+	GAME_REPORT:           "GAME_REPORT",
+	LOBBY_OPEN:            "LOBBY_OPEN",
+	PLAYER_READY:          "PLAYER_READY",
+	PLAYER_UNREADY:        "PLAYER_UNREADY",
+	HOST_ASSIGNED:         "HOST_ASSIGNED",
+	PLAYER_KICKED:         "PLAYER_KICKED",
+	COUNTDOWN_CANCELLED:   "COUNTDOWN_CANCELLED",
+	AFK_WARNING:           "AFK_WARNING",
+	AFK_MISSED:            "AFK_MISSED",
+	AFK_SKIPPED:           "AFK_SKIPPED",
+	KICK_VOTE:             "KICK_VOTE",
+	PLAYER_CLEARED:        "PLAYER_CLEARED",
+	CHECK_SHARED:          "CHECK_SHARED",
+}
+
+func (e notificationEvent) String() string {
+	if int(e) < len(notificationEventNames) {
+		return notificationEventNames[e]
+	}
+
+	return "UNKNOWN"
+}
+
 type Notification struct {
 	eventType notificationEvent
 	info      string
 }
 
+// ---- chat
+type ChatMessage struct {
+	sender    string
+	channel   string
+	recipient string
+	text      string
+	sentAt    time.Time
+}
+
+// encode packs the message into CHAT_MSG notification info
+func (m ChatMessage) encode() string {
+	return strings.Join([]string{m.channel, m.sender, m.recipient, m.text}, "@@")
+}
+
 // ---- custom errors
 var nameCollisionError = errors.New("there is already a player with the same name in the session")
 var sessionStartedError = errors.New("game session has already started, try to connect later")
 var channelClosedError = errors.New("this player's Notification channel has been closed")
 var playerRemovedError = errors.New("this player has already left the session")
-var noExposedPlayerError = errors.New("you haven't exposed anyone during last night")
+var emptyNameError = errors.New("player's name can't be empty")
+var notConnectedError = errors.New("you are not connected to a game session, join a server first")
+var alreadyConnectedError = errors.New("you are already in the game session")
+var unknownCommandError = errors.New("unknown command")
+
+// ---- chat moderation errors, reported to clients with a status code
+var emptyChatMsgError = status.Error(codes.InvalidArgument, "message can't be empty")
+var chatMsgTooLongError = status.Error(codes.InvalidArgument, fmt.Sprintf("message can't be longer than %d characters", CHAT_MAX_MSG_LEN))
+var chatRateLimitError = status.Error(codes.ResourceExhausted, "you are sending messages too fast, wait a bit")
+var playerMutedError = status.Error(codes.PermissionDenied, "you have been muted by a moderator")
+var slowConsumerError = status.Error(codes.ResourceExhausted, "too many notifications are pending, subscribe again to continue")
+var notModeratorError = status.Error(codes.PermissionDenied, "only moderators can mute players")
+var notInLobbyError = status.Error(codes.FailedPrecondition, "this can be done only in the lobby, before a game or after it has ended")
+var notHostError = status.Error(codes.PermissionDenied, "only the host can do that")
+var notEnoughPlayersError = status.Error(codes.FailedPrecondition, fmt.Sprintf("at least %d players are needed to start the game", PLAYERS_LOWER_LIM))
+var notAllReadyError = status.Error(codes.FailedPrecondition, "not everyone is ready yet")
+var playerNotFoundError = status.Error(codes.NotFound, "there is no player with this name in the session")
+var kickSelfError = status.Error(codes.InvalidArgument, "you can't kick yourself, disconnect instead")
+var roomNotFoundError = status.Error(codes.NotFound, "there is no room with this invite code")
+var wrongPasswordError = status.Error(codes.PermissionDenied, "wrong room password")
+var wrongModeratorTokenError = status.Error(codes.PermissionDenied, "wrong moderator token")
+var roomFullError = status.Error(codes.ResourceExhausted, "the room is full")
+var tooManyRoomsError = status.Error(codes.ResourceExhausted, "there are too many rooms on the server, try again later")
+var unknownVisibilityError = status.Error(codes.InvalidArgument, fmt.Sprintf("room visibility must be %s or %s", VISIBILITY_PUBLIC, VISIBILITY_UNLISTED))
+var maxPlayersError = status.Error(codes.InvalidArgument, fmt.Sprintf("max players must be between %d and %d", PLAYERS_LOWER_LIM, ROOM_SIZE_LIM))
+var unknownRulesetError = status.Error(codes.InvalidArgument, fmt.Sprintf("unknown ruleset, expected %s, %s, %s or %s", RULESET_SERVER, RULESET_CLASSIC, RULESET_TRIAL, RULESET_EXTENDED))
+var kickVoteClosedError = status.Error(codes.FailedPrecondition, "players may vote to kick only in the lobby and during the day")
+var ghostKickVoteError = status.Error(codes.PermissionDenied, "ghosts can't vote to kick")
+var kickGhostError = status.Error(codes.FailedPrecondition, "this player is already a ghost")
+var unknownRoleError = status.Error(codes.InvalidArgument, "unknown role, expected civilian, detective, mafia, don, maniac or jester")
+var preferenceConflictError = status.Error(codes.InvalidArgument, "you can't both prefer and avoid the same role")
+var notInGameError = status.Error(codes.FailedPrecondition, "there is no game in progress")
+var ghostChannelOnlyError = status.Error(codes.PermissionDenied, "ghosts may only talk in the ghosts channel")
+var ghostsChannelError = status.Error(codes.PermissionDenied, "only ghosts can talk in the ghosts channel")
+var mafiaChannelError = status.Error(codes.PermissionDenied, "only mafia members can talk in the mafia channel")
+var loversChannelError = status.Error(codes.PermissionDenied, "only the lovers can talk in the lovers channel")
+var nightChatError = status.Error(codes.PermissionDenied, "only mafia can communicate at night")
+var directMessagesDisabledError = status.Error(codes.PermissionDenied, "direct messages are disabled in this game")
+var nightDirectMessageError = status.Error(codes.PermissionDenied, "direct messages are allowed only during the day")
+var directMessageSelfError = status.Error(codes.InvalidArgument, "you can't send messages to yourself")
+var directMessageGhostError = status.Error(codes.PermissionDenied, "ghosts can't receive direct messages")
+var closedVotingError = status.Error(codes.FailedPrecondition, "votes are secret in this game")
@@ -0,0 +1,272 @@
+"use strict";
+
+const state = {
+  socket: null,
+  name: "",
+  players: [],
+  dead: new Set(),
+  votes: new Map(),
+  // roles of the known teammates by name
+  team: new Map(),
+};
+
+const $ = (id) => document.getElementById(id);
+
+function send(cmd) {
+  if (state.socket && state.socket.readyState === WebSocket.OPEN) {
+    state.socket.send(JSON.stringify(cmd));
+  }
+}
+
+function append(listId, text, className) {
+  const list = $(listId);
+  const item = document.createElement("li");
+  item.textContent = text;
+  if (className) {
+    item.className = className;
+  }
+  list.appendChild(item);
+  list.scrollTop = list.scrollHeight;
+}
+
+function appendChat(entry) {
+  let prefix = entry.sender;
+  if (entry.channel === "direct") {
+    prefix = "[direct] " + entry.sender + " -> " + entry.recipient;
+  } else if (entry.channel !== "public") {
+    prefix = "[" + entry.channel + "] " + entry.sender;
+  }
+  append("chat", prefix + ": " + entry.msg, "channel-" + entry.channel);
+}
+
+function renderPlayers() {
+  const list = $("players");
+  list.innerHTML = "";
+  const counts = new Map();
+  for (const target of state.votes.values()) {
+    counts.set(target, (counts.get(target) || 0) + 1);
+  }
+  for (const name of state.players) {
+    const item = document.createElement("li");
+    const label = document.createElement("span");
+    label.textContent = counts.has(name) ? name + " [" + counts.get(name) + "]" : name;
+    if (state.team.has(name)) {
+      label.textContent += " <" + state.team.get(name) + ">";
+    }
+    item.appendChild(label);
+    if (name === state.name) {
+      item.classList.add("me");
+    }
+    if (state.dead.has(name)) {
+      item.classList.add("dead");
+    } else if (name !== state.name) {
+      const vote = document.createElement("button");
+      vote.textContent = "Vote";
+      vote.onclick = () => send({ cmd: "vote", target: name });
+      item.appendChild(vote);
+      if (["don", "detective"].includes($("role").textContent)) {
+        const check = document.createElement("button");
+        check.textContent = "Check";
+        check.onclick = () => send({ cmd: "check", target: name });
+        item.appendChild(check);
+      }
+    }
+    list.appendChild(item);
+  }
+}
+
+function setPhase(phase) {
+  $("phase").textContent = phase;
+  document.body.classList.toggle("night", phase === "night");
+}
+
+function onNotification(msg) {
+  switch (msg.event) {
+    case "CHAT_MSG": {
+      const [channel, sender, recipient, ...text] = msg.info.split("@@");
+      appendChat({ channel: channel, sender: sender, recipient: recipient, msg: text.join("@@") });
+      return;
+    }
+    case "ROLE_ASSIGNED": {
+      const [role, ...team] = msg.info.split("@@");
+      $("role").textContent = role;
+      state.team = new Map(team.map((mate) => mate.split(" ")));
+      break;
+    }
+    case "PHASE_START_DAY":
+      setPhase("day");
+      state.votes.clear();
+      break;
+    case "PHASE_START_NIGHT":
+      setPhase("night");
+      state.votes.clear();
+      $("verdict").hidden = true;
+      break;
+    case "NOMINATION_MADE": {
+      const [nominator, target] = msg.info.split("@@");
+      state.votes.set(nominator, target);
+      break;
+    }
+    case "VOTE_ABSTAINED":
+      state.votes.delete(msg.info);
+      break;
+    case "REVOTE_START":
+      state.votes.clear();
+      break;
+    case "VERDICT_START":
+      $("accused").textContent = msg.info.split("@@")[0];
+      $("verdict").hidden = false;
+      break;
+    case "VERDICT_RESULT":
+      $("verdict").hidden = true;
+      break;
+    case "VOTE_CAST": {
+      const [voter, target] = msg.info.split("@@");
+      state.votes.set(voter, target);
+      break;
+    }
+    case "SESSION_START":
+      state.dead.clear();
+      state.votes.clear();
+      break;
+    case "SESSION_END":
+      setPhase("ended");
+      break;
+    case "PLAYER_ELIMINATED": {
+      const name = msg.info.split(" ")[0];
+      state.dead.add(name);
+      // the votes against a player removed during the day are withdrawn
+      for (const [voter, target] of state.votes) {
+        if (target === name) {
+          state.votes.delete(voter);
+        }
+      }
+      break;
+    }
+  }
+
+  append("events", msg.text);
+  if (["CLIENT_CONNECTED", "CLIENT_DISCONNECTED", "SESSION_START", "PLAYER_ELIMINATED"].includes(msg.event)) {
+    send({ cmd: "players" });
+  }
+  renderPlayers();
+}
+
+function onMessage(raw) {
+  const msg = JSON.parse(raw.data);
+  switch (msg.type) {
+    case "connected":
+      $("room").textContent = msg.room || "main";
+      $("connect-form").hidden = true;
+      $("game").hidden = false;
+      send({ cmd: "players" });
+      send({ cmd: "history" });
+      break;
+    case "history":
+      $("chat").innerHTML = "";
+      (msg.history || []).forEach(appendChat);
+      break;
+    case "checks":
+      if (!msg.checks) {
+        append("events", "No checks have been made yet");
+      }
+      (msg.checks || []).forEach((check) => {
+        const result = check.role === "don" ? (check.found ? "the Detective" : "not the Detective") : (check.found ? "a member of Mafia" : "not a member of Mafia");
+        append("events", "Night " + check.round + ", " + check.checker + " (" + check.role + "): " + check.target + " is " + result);
+      });
+      break;
+    case "disconnected":
+      state.socket.close();
+      break;
+    case "players":
+      state.players = (msg.players || []).filter((name) => name !== "");
+      renderPlayers();
+      break;
+    case "notification":
+      onNotification(msg);
+      break;
+    case "error":
+      if ($("game").hidden) {
+        // joining has failed, e.g. a wrong room code or password
+        alert(msg.info);
+        state.socket.close();
+        break;
+      }
+      append("events", msg.info, "error");
+      break;
+  }
+}
+
+// connect opens the socket and sends the connect or create command once it is ready
+function connect(command) {
+  const scheme = location.protocol === "https:" ? "wss://" : "ws://";
+  state.socket = new WebSocket(scheme + location.host + "/ws");
+  state.name = command.name;
+  state.socket.onopen = () => send(command);
+  state.socket.onmessage = onMessage;
+  state.socket.onclose = () => {
+    $("connect-form").hidden = false;
+    $("game").hidden = true;
+    $("role").textContent = "-";
+    $("room").textContent = "main";
+    setPhase("lobby");
+    $("events").innerHTML = "";
+    $("chat").innerHTML = "";
+  };
+}
+
+// the moderator token of the server is passed in the page address as ?moderator=<token>
+const moderatorToken = new URLSearchParams(location.search).get("moderator") || "";
+
+$("connect-form").onsubmit = (e) => {
+  e.preventDefault();
+  const name = $("nickname").value.trim();
+  if (name !== "") {
+    connect({ cmd: "connect", name: name, room: $("room-code").value.trim(), password: $("password").value, moderatorToken: moderatorToken });
+  }
+};
+
+$("create").onclick = () => {
+  const name = $("nickname").value.trim();
+  if (name !== "") {
+    connect({
+      cmd: "create",
+      name: name,
+      password: $("password").value,
+      moderatorToken: moderatorToken,
+      visibility: $("visibility").value,
+      maxPlayers: Number($("max-players").value) || 0,
+      ruleset: $("ruleset").value,
+    });
+  }
+};
+
+$("chat-form").onsubmit = (e) => {
+  e.preventDefault();
+  const msg = $("chat-msg").value.trim();
+  const channel = $("chat-channel").value;
+  const [command, target] = msg.split(/\s+/, 2);
+  if (command === "/mute" || command === "/unmute" || command === "/kick" || command === "/votekick" || command === "/expose") {
+    send({ cmd: command.slice(1), target: target || "" });
+    $("chat-msg").value = "";
+  } else if (msg !== "") {
+    send({ cmd: "chat", msg: msg, channel: channel, target: channel === "direct" ? $("chat-recipient").value.trim() : "" });
+    $("chat-msg").value = "";
+  }
+};
+
+$("chat-channel").onchange = () => {
+  $("chat-recipient").hidden = $("chat-channel").value !== "direct";
+};
+
+$("guilty").onclick = () => send({ cmd: "verdict", guilty: true });
+$("innocent").onclick = () => send({ cmd: "verdict", guilty: false });
+$("abstain").onclick = () => send({ cmd: "abstain" });
+$("skip").onclick = () => send({ cmd: "skip" });
+$("expose").onclick = () => send({ cmd: "expose" });
+$("checks").onclick = () => send({ cmd: "checks" });
+$("ready").onclick = () => send({ cmd: "ready" });
+$("unready").onclick = () => send({ cmd: "unready" });
+$("start").onclick = () => send({ cmd: "start" });
+$("prefer").onchange = $("avoid").onchange = () => send({ cmd: "prefer", prefer: $("prefer").value, avoid: $("avoid").value });
+$("disconnect").onclick = () => send({ cmd: "disconnect" });
@@ -0,0 +1,382 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mafia-core/proto"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/status"
+)
+
+// static files of the browser client
+//
+//go:embed web
+var webFiles embed.FS
+
+// ---- websocket commands, named the same way as the CLI client ones
+const (
+	WS_CONNECT    = "connect"
+	WS_CREATE     = "create"
+	WS_ROOMS      = "rooms"
+	WS_DISCONNECT = "disconnect"
+	WS_PLAYERS    = "players"
+	WS_VOTE       = "vote"
+	WS_ABSTAIN    = "abstain"
+	WS_CHECK      = "check"
+	WS_NOMINATE   = "nominate"
+	WS_VERDICT    = "verdict"
+	WS_END_DAY    = "skip"
+	WS_READY      = "ready"
+	WS_UNREADY    = "unready"
+	WS_START      = "start"
+	WS_KICK       = "kick"
+	WS_VOTE_KICK  = "votekick"
+	WS_PREFER     = "prefer"
+	WS_EXPOSE     = "expose"
+	WS_CHECKS     = "checks"
+	WS_CHAT       = "chat"
+	WS_HISTORY    = "history"
+	WS_MUTE       = "mute"
+	WS_UNMUTE     = "unmute"
+)
+
+// ---- websocket server messages
+const (
+	WS_CONNECTED    = "connected"
+	WS_DISCONNECTED = "disconnected"
+	WS_NOTIFICATION = "notification"
+	WS_PLAYERS_LIST = "players"
+	WS_ROOMS_LIST   = "rooms"
+	WS_CHAT_HISTORY = "history"
+	WS_CHECKS_LOG   = "checks"
+	WS_ERROR        = "error"
+)
+
+// wsCommand is a request from the browser client, it mirrors the Mafia gRPC service
+type wsCommand struct {
+	Cmd        string `json:"cmd"`
+	Name       string `json:"name,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Msg        string `json:"msg,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+	Guilty     bool   `json:"guilty,omitempty"`
+	Room       string `json:"room,omitempty"`
+	Password   string `json:"password,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+	MaxPlayers uint32 `json:"maxPlayers,omitempty"`
+	Ruleset    string `json:"ruleset,omitempty"`
+	Prefer     string `json:"prefer,omitempty"`
+	Avoid      string `json:"avoid,omitempty"`
+	// ModeratorToken is sent with connect and create to get the moderator rights
+	ModeratorToken string `json:"moderatorToken,omitempty"`
+}
+
+// wsRoom is a public room shown to the browser client before it connects
+type wsRoom struct {
+	Code       string `json:"code"`
+	MaxPlayers uint32 `json:"maxPlayers,omitempty"`
+	Ruleset    string `json:"ruleset"`
+	Players    uint32 `json:"players"`
+	Status     string `json:"status"`
+	Locked     bool   `json:"locked,omitempty"`
+}
+
+// wsChatEntry is a chat history record for the browser client
+type wsChatEntry struct {
+	Sender    string `json:"sender"`
+	Channel   string `json:"channel"`
+	Recipient string `json:"recipient,omitempty"`
+	Msg       string `json:"msg"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// wsCheck is a night check from the investigation log for the browser client
+type wsCheck struct {
+	Round   int    `json:"round"`
+	Checker string `json:"checker"`
+	Role    string `json:"role"`
+	Target  string `json:"target"`
+	Found   bool   `json:"found"`
+}
+
+// wsMessage is a response or a notification sent to the browser client
+type wsMessage struct {
+	Type     string        `json:"type"`
+	Event    string        `json:"event,omitempty"`
+	Info     string        `json:"info,omitempty"`
+	Text     string        `json:"text,omitempty"`
+	Sequence uint64        `json:"sequence,omitempty"`
+	Players  []string      `json:"players,omitempty"`
+	History  []wsChatEntry `json:"history,omitempty"`
+	Checks   []wsCheck     `json:"checks,omitempty"`
+	Rooms    []wsRoom      `json:"rooms,omitempty"`
+	Room     string        `json:"room,omitempty"`
+}
+
+// wsClient binds a single websocket connection to a player of the game session
+type wsClient struct {
+	conn        *websocket.Conn
+	ctx         context.Context
+	id          uint64
+	isConnected bool
+	sendLock    sync.Mutex
+}
+
+func (wc *wsClient) send(msg wsMessage) error {
+	wc.sendLock.Lock()
+	defer wc.sendLock.Unlock()
+	return websocket.JSON.Send(wc.conn, msg)
+}
+
+func (wc *wsClient) sendError(err error) {
+	if err := wc.send(wsMessage{Type: WS_ERROR, Info: status.Convert(err).Message()}); err != nil {
+		log.Printf("websocket send error: %v\n", err)
+	}
+}
+
+func (wc *wsClient) forwardNotifications(s *server, id uint64) {
+	r, err := s.roomOf(id)
+	if err != nil {
+		log.Printf("ClientId %d websocket subscription error: %v\n", id, err)
+		return
+	}
+	subscription, err := r.session.SubscribeToPlayersNotifications(id, 0)
+	if err != nil {
+		log.Printf("ClientId %d websocket subscription error: %v\n", id, err)
+		return
+	}
+	defer func() {
+		subscription.cancel()
+	}()
+
+	var lastSeq uint64
+	record, err := subscription.pop(wc.ctx)
+	for ; err == nil || err == slowConsumerError; record, err = subscription.pop(wc.ctx) {
+		if err == slowConsumerError {
+			// whatever didn't fit is still in the history, so continue right after the last sent notification
+			subscription.cancel()
+			if subscription, err = r.session.SubscribeToPlayersNotifications(id, lastSeq); err != nil {
+				break
+			}
+			continue
+		}
+
+		lastSeq = record.sequence
+		msg := wsMessage{
+			Type:     WS_NOTIFICATION,
+			Event:    record.event.eventType.String(),
+			Info:     record.event.info,
+			Text:     formatNotification(record.event),
+			Sequence: record.sequence,
+		}
+		if err := wc.send(msg); err != nil {
+			return
+		}
+	}
+
+	log.Printf("ClientId %d websocket notification error: %v\n", id, err)
+}
+
+func (wc *wsClient) connect(s *server, info *proto.ClientInfo) error {
+	if wc.isConnected {
+		return alreadyConnectedError
+	}
+	assignedId, err := s.Connect(context.Background(), info)
+	if err != nil {
+		return err
+	}
+	wc.id, wc.isConnected = assignedId.Id, true
+	r, err := s.roomOf(wc.id)
+	if err != nil {
+		return err
+	}
+	if err := wc.send(wsMessage{Type: WS_CONNECTED, Info: info.Name, Room: r.code}); err != nil {
+		return err
+	}
+	go wc.forwardNotifications(s, wc.id)
+	return nil
+}
+
+func (wc *wsClient) handle(s *server, cmd wsCommand) error {
+	if cmd.Cmd != WS_CONNECT && cmd.Cmd != WS_CREATE && cmd.Cmd != WS_ROOMS && !wc.isConnected {
+		return notConnectedError
+	}
+
+	ctx := context.Background()
+	id := &proto.ClientId{Id: wc.id}
+	switch cmd.Cmd {
+	case WS_CONNECT:
+		return wc.connect(s, &proto.ClientInfo{Name: cmd.Name, Room: cmd.Room, Password: cmd.Password, ModeratorToken: cmd.ModeratorToken})
+	case WS_CREATE:
+		if wc.isConnected {
+			return alreadyConnectedError
+		}
+		room, err := s.CreateRoom(ctx, &proto.RoomReq{Password: cmd.Password, Visibility: cmd.Visibility, MaxPlayers: cmd.MaxPlayers, Ruleset: cmd.Ruleset})
+		if err != nil {
+			return err
+		}
+		return wc.connect(s, &proto.ClientInfo{Name: cmd.Name, Room: room.Code, Password: cmd.Password, ModeratorToken: cmd.ModeratorToken})
+	case WS_ROOMS:
+		list, err := s.ListRooms(ctx, &proto.EmptyMsg{})
+		if err != nil {
+			return err
+		}
+		msg := wsMessage{Type: WS_ROOMS_LIST}
+		for _, room := range list.Rooms {
+			msg.Rooms = append(msg.Rooms, wsRoom{
+				Code:       room.Code,
+				MaxPlayers: room.MaxPlayers,
+				Ruleset:    room.Ruleset,
+				Players:    room.Players,
+				Status:     room.Status,
+				Locked:     room.Locked,
+			})
+		}
+		return wc.send(msg)
+	case WS_DISCONNECT:
+		wc.isConnected = false
+		if _, err := s.Disconnect(ctx, id); err != nil {
+			return err
+		}
+		return wc.send(wsMessage{Type: WS_DISCONNECTED})
+	case WS_PLAYERS:
+		list, err := s.ShowPlayersList(ctx, id)
+		if err != nil {
+			return err
+		}
+		return wc.send(wsMessage{Type: WS_PLAYERS_LIST, Players: list.Players})
+	case WS_VOTE:
+		_, err := s.Vote(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_ABSTAIN:
+		_, err := s.Abstain(ctx, id)
+		return err
+	case WS_CHECK:
+		_, err := s.Check(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_NOMINATE:
+		_, err := s.Nominate(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_VERDICT:
+		_, err := s.Verdict(ctx, &proto.VerdictReq{Id: id, Guilty: cmd.Guilty})
+		return err
+	case WS_END_DAY:
+		_, err := s.EndDay(ctx, id)
+		return err
+	case WS_READY:
+		_, err := s.Ready(ctx, id)
+		return err
+	case WS_UNREADY:
+		_, err := s.Unready(ctx, id)
+		return err
+	case WS_START:
+		_, err := s.StartGame(ctx, id)
+		return err
+	case WS_KICK:
+		_, err := s.Kick(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_VOTE_KICK:
+		_, err := s.VoteKick(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_PREFER:
+		_, err := s.SetRolePreference(ctx, &proto.RolePreferenceReq{Id: id, Prefer: cmd.Prefer, Avoid: cmd.Avoid})
+		return err
+	case WS_EXPOSE:
+		_, err := s.Expose(ctx, &proto.ExposeReq{Id: id.Id, Target: cmd.Target})
+		return err
+	case WS_CHAT:
+		_, err := s.Chat(ctx, &proto.ChatMsg{Id: id, Msg: cmd.Msg, Channel: cmd.Channel, Recipient: cmd.Target})
+		return err
+	case WS_MUTE:
+		_, err := s.Mute(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_UNMUTE:
+		_, err := s.Unmute(ctx, &proto.ClientReq{Id: id, Target: &proto.ClientInfo{Name: cmd.Target}})
+		return err
+	case WS_HISTORY:
+		history, err := s.GetChatHistory(ctx, &proto.ChatHistoryReq{Id: id, Channel: cmd.Channel})
+		if err != nil {
+			return err
+		}
+		msg := wsMessage{Type: WS_CHAT_HISTORY}
+		for _, entry := range history.Messages {
+			msg.History = append(msg.History, wsChatEntry{
+				Sender:    entry.Sender,
+				Channel:   entry.Channel,
+				Recipient: entry.Recipient,
+				Msg:       entry.Msg,
+				Timestamp: entry.Timestamp,
+			})
+		}
+		return wc.send(msg)
+	case WS_CHECKS:
+		checks, err := s.GetInvestigations(ctx, id)
+		if err != nil {
+			return err
+		}
+		msg := wsMessage{Type: WS_CHECKS_LOG}
+		for _, check := range checks.Checks {
+			msg.Checks = append(msg.Checks, wsCheck{Round: int(check.Round), Checker: check.Checker, Role: check.Role, Target: check.Target, Found: check.Found})
+		}
+		return wc.send(msg)
+	default:
+		return unknownCommandError
+	}
+}
+
+// ServeWebSocket handles a browser client connection, speaking JSON equivalent of the Mafia service
+func (s *server) ServeWebSocket(conn *websocket.Conn) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wc := &wsClient{conn: conn, ctx: ctx}
+	defer func() {
+		if wc.isConnected {
+			if _, err := s.Disconnect(context.Background(), &proto.ClientId{Id: wc.id}); err != nil {
+				log.Printf("ClientId %d websocket disconnect error: %v\n", wc.id, err)
+			}
+		}
+	}()
+
+	for {
+		var cmd wsCommand
+		if err := websocket.JSON.Receive(conn, &cmd); err != nil {
+			if err != io.EOF {
+				log.Printf("websocket receive error: %v\n", err)
+			}
+			return
+		}
+
+		if err := wc.handle(s, cmd); err != nil {
+			wc.sendError(err)
+		}
+	}
+}
+
+func (s *server) ServeWeb(port int) {
+	static, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		log.Fatalf("failed to load web client: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.Handle("/ws", websocket.Handler(s.ServeWebSocket))
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if _, err := notificationStats.WriteTo(w); err != nil {
+			log.Printf("failed to write metrics: %v\n", err)
+		}
+	})
+	log.Printf("WEB listening at :%d", port)
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+		log.Fatalf("failed to serve web: %v", err)
+	}
+}
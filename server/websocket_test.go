@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+// dialWebSocket opens a browser client connection to the test server
+func dialWebSocket(t *testing.T, s *server) *websocket.Conn {
+	t.Helper()
+	web := httptest.NewServer(websocket.Handler(s.ServeWebSocket))
+	t.Cleanup(web.Close)
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(web.URL, "http"), "", web.URL)
+	if err != nil {
+		t.Fatalf("couldn't open the websocket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// exchange sends the command and returns the first reply that isn't a notification
+func exchange(t *testing.T, conn *websocket.Conn, cmd wsCommand) wsMessage {
+	t.Helper()
+	if err := websocket.JSON.Send(conn, cmd); err != nil {
+		t.Fatalf("couldn't send %s: %v", cmd.Cmd, err)
+	}
+	for {
+		var msg wsMessage
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			t.Fatalf("no reply to %s: %v", cmd.Cmd, err)
+		}
+		if msg.Type != WS_NOTIFICATION {
+			return msg
+		}
+	}
+}
+
+func TestWebSocketSession(t *testing.T) {
+	s := newTestServer(DefaultRuleset)
+	connect(t, s, "grpc")
+	conn := dialWebSocket(t, s)
+
+	if msg := exchange(t, conn, wsCommand{Cmd: WS_PLAYERS}); msg.Type != WS_ERROR {
+		t.Errorf("a command before connecting should fail, got %+v", msg)
+	}
+	if msg := exchange(t, conn, wsCommand{Cmd: WS_CONNECT, Name: "web"}); msg.Type != WS_CONNECTED || msg.Info != "web" || msg.Room != MAIN_ROOM {
+		t.Fatalf("couldn't connect over the websocket: %+v", msg)
+	}
+	if msg := exchange(t, conn, wsCommand{Cmd: WS_PLAYERS}); msg.Type != WS_PLAYERS_LIST || len(msg.Players) != 2 {
+		t.Errorf("the browser client should see both players, got %+v", msg)
+	}
+	if msg := exchange(t, conn, wsCommand{Cmd: "dance"}); msg.Type != WS_ERROR {
+		t.Errorf("an unknown command should fail, got %+v", msg)
+	}
+	if msg := exchange(t, conn, wsCommand{Cmd: WS_DISCONNECT}); msg.Type != WS_DISCONNECTED {
+		t.Errorf("couldn't disconnect over the websocket: %+v", msg)
+	}
+	if cnt := s.rooms[MAIN_ROOM].session.GetPlayersCount(); cnt != 1 {
+		t.Errorf("%d players are left in the room after the browser client has left", cnt)
+	}
+}